@@ -0,0 +1,112 @@
+// Package app wires the stores, storage and configuration the HTTP API
+// depends on.
+package app
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/cache"
+	"github.com/Hamedblue1381/restaurant-reserve/config"
+	"github.com/Hamedblue1381/restaurant-reserve/health"
+	"github.com/Hamedblue1381/restaurant-reserve/metrics"
+	"github.com/Hamedblue1381/restaurant-reserve/middleware"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/storage"
+	"github.com/Hamedblue1381/restaurant-reserve/tracing"
+	"gorm.io/gorm"
+)
+
+// App holds every dependency of the router. main builds it with New, tests
+// can fill the fields with fakes instead.
+type App struct {
+	Config  *config.Config
+	Tokens  *middleware.JWT
+	Blobs   storage.BlobStore
+	Metrics *metrics.Metrics
+	Health  *health.Checker
+
+	Users        models.UserStore
+	Foods        models.FoodStore
+	Sides        models.SidesStore
+	MealTypes    models.MealTypeStore
+	Reservations models.ReservationStore
+	Reviews      models.ReviewStore
+	Reports      models.ReportStore
+	Imports      models.ImportStore
+	Search       models.SearchStore
+	Idempotency  models.IdempotencyStore
+	Audit        models.AuditStore
+	Trash        models.TrashStore
+}
+
+// New builds the application backed by db, whose queries it instruments.
+func New(cfg *config.Config, db *gorm.DB, blobs storage.BlobStore) *App {
+	m := metrics.New()
+	if err := m.Instrument(db); err != nil {
+		slog.Warn("database metrics disabled", "error", err)
+	}
+	if err := tracing.Instrument(db); err != nil {
+		slog.Warn("database tracing disabled", "error", err)
+	}
+
+	checker := health.New(time.Duration(cfg.Server.HealthTimeout))
+	checker.Add("database", health.Database(db))
+	// with AutoMigrate the schema is not tracked by migrations
+	if !cfg.Database.AutoMigrate {
+		checker.Add("migrations", health.Migrations(db))
+	}
+
+	a := &App{
+		Config:  cfg,
+		Tokens:  middleware.NewJWT(cfg.Auth.JWTSecret, time.Duration(cfg.Auth.TokenTTL)),
+		Blobs:   blobs,
+		Metrics: m,
+		Health:  checker,
+
+		Users:        models.NewUserHandler(db),
+		Foods:        models.NewFoodHandler(db),
+		Sides:        models.NewSidesHandler(db),
+		MealTypes:    models.NewMealTypeHandler(db),
+		Reservations: models.NewReservationHandler(db),
+		Reviews:      models.NewReviewHandler(db),
+		Reports:      models.NewReportHandler(db),
+		Imports:      models.NewImportHandler(db),
+		Search:       models.NewSearchHandler(db),
+		Idempotency:  models.NewIdempotencyHandler(db),
+		Audit:        models.NewAuditHandler(db),
+		Trash:        models.NewTrashHandler(db),
+	}
+
+	backend, err := newCacheBackend(cfg.Cache)
+	if err != nil {
+		slog.Warn("catalog cache disabled", "error", err)
+	}
+	// reads fall back to the database while the cache is down, so it has no
+	// readiness check
+	if backend != nil {
+		catalog := cache.NewCatalog(backend, time.Duration(cfg.Cache.TTL), m)
+		a.Foods = catalog.Foods(a.Foods)
+		a.Sides = catalog.Sides(a.Sides)
+		a.MealTypes = catalog.MealTypes(a.MealTypes)
+		a.Reviews = catalog.Reviews(a.Reviews)
+		a.Imports = catalog.Imports(a.Imports)
+		a.Trash = catalog.Trash(a.Trash)
+	}
+	return a
+}
+
+// newCacheBackend returns the configured cache, or nil when it is disabled.
+func newCacheBackend(cfg config.CacheConfig) (cache.Backend, error) {
+	switch cfg.Backend {
+	case config.CacheMemory:
+		return cache.NewMemory(cfg.Size), nil
+	case config.CacheRedis:
+		redis, err := cache.NewRedis(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return redis, nil
+	}
+	return nil, nil
+}
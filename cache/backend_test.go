@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+var ctx = context.Background()
+
+// backend is a Backend under test with a way to move its clock forward.
+type backend struct {
+	name    string
+	backend Backend
+	advance func(time.Duration)
+}
+
+func backends(t *testing.T) []backend {
+	memory := NewMemory(100)
+	now := time.Now()
+	memory.now = func() time.Time { return now }
+
+	server := miniredis.RunT(t)
+	redis, err := NewRedis("redis://" + server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { redis.Close() })
+
+	return []backend{
+		{"memory", memory, func(d time.Duration) { now = now.Add(d) }},
+		{"redis", redis, server.FastForward},
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	for _, b := range backends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			if err := b.backend.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			if value, ok, err := b.backend.Get(ctx, "key"); err != nil || !ok || string(value) != "value" {
+				t.Fatalf("Get() before expiry = %q, %v, %v", value, ok, err)
+			}
+
+			b.advance(time.Minute + time.Second)
+			if value, ok, err := b.backend.Get(ctx, "key"); err != nil || ok {
+				t.Errorf("Get() after expiry = %q, %v, %v, want a miss", value, ok, err)
+			}
+		})
+	}
+}
+
+func TestCounter(t *testing.T) {
+	for _, b := range backends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			if n, err := b.backend.Counter(ctx, generationKey); err != nil || n != 0 {
+				t.Fatalf("Counter() of a new key = %d, %v, want 0", n, err)
+			}
+			for want := int64(1); want <= 3; want++ {
+				if n, err := b.backend.Incr(ctx, generationKey); err != nil || n != want {
+					t.Fatalf("Incr() = %d, %v, want %d", n, err, want)
+				}
+			}
+			// the generation never expires, or old entries would become valid again
+			b.advance(24 * time.Hour)
+			if n, err := b.backend.Counter(ctx, generationKey); err != nil || n != 3 {
+				t.Errorf("Counter() = %d, %v, want 3", n, err)
+			}
+		})
+	}
+}
+
+func TestMemoryEviction(t *testing.T) {
+	m := NewMemory(2)
+	m.Set(ctx, "a", []byte("a"), time.Minute)
+	m.Set(ctx, "b", []byte("b"), time.Minute)
+	m.Get(ctx, "a")
+	m.Set(ctx, "c", []byte("c"), time.Minute)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := m.Get(ctx, key); ok != want {
+			t.Errorf("%s cached = %v, want %v", key, ok, want)
+		}
+	}
+
+	// counters are not values, so filling the cache never evicts them
+	m.Incr(ctx, generationKey)
+	for i := 0; i < 10; i++ {
+		m.Set(ctx, fmt.Sprint(i), nil, time.Minute)
+	}
+	if n, _ := m.Counter(ctx, generationKey); n != 1 {
+		t.Errorf("generation = %d after filling the cache, want 1", n)
+	}
+}
@@ -0,0 +1,25 @@
+// Package cache keeps the catalog reads of the API, foods, sides and meal
+// types, in memory or in Redis, in front of the database.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Backend stores byte values under string keys. Implementations must be safe
+// for concurrent use.
+type Backend interface {
+	// Get returns the value of key, and false when it is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key until ttl has passed.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr atomically increments the counter stored under key, which never
+	// expires, and returns its new value. A missing counter starts at 0.
+	Incr(ctx context.Context, key string) (int64, error)
+	// Counter returns the value of the counter stored under key, or 0.
+	Counter(ctx context.Context, key string) (int64, error)
+	// Ping checks that the backend can be reached.
+	Ping(ctx context.Context) error
+	Close() error
+}
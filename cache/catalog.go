@@ -0,0 +1,351 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/metrics"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+)
+
+// generationKey holds the generation of the catalog, which every cache key
+// embeds. Incrementing it invalidates every entry at once: foods embed their
+// meal type and ratings change with reviews, so finer grained invalidation
+// is easy to get wrong, while the menu only changes a few times a day.
+const generationKey = "catalog:generation"
+
+// Catalog caches the reads of the food, sides and meal type stores it wraps,
+// and invalidates them whenever a wrapped store writes to the catalog.
+// Entries are read under the generation current before the database is
+// queried, so a value loaded concurrently with a write is never served once
+// the write is done.
+//
+// The cache is only an optimization: when the backend fails, reads go to the
+// database and the error is logged.
+type Catalog struct {
+	backend Backend
+	ttl     time.Duration
+	metrics *metrics.Metrics
+}
+
+// NewCatalog caches the catalog in backend for at most ttl.
+func NewCatalog(backend Backend, ttl time.Duration, m *metrics.Metrics) *Catalog {
+	return &Catalog{backend, ttl, m}
+}
+
+// cached returns the value stored under key, or loads and stores it.
+// Values are gob encoded to keep the fields hidden from JSON, such as the
+// timestamps of the rows.
+func cached[T any](ctx context.Context, c *Catalog, resource, key string, load func() (*T, error)) (*T, error) {
+	generation, err := c.backend.Counter(ctx, generationKey)
+	if err != nil {
+		c.failed(ctx, resource, "read", err)
+		return load()
+	}
+	key = fmt.Sprintf("catalog:%d:%s:%s", generation, resource, key)
+
+	data, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		c.failed(ctx, resource, "read", err)
+		return load()
+	}
+	if ok {
+		var value T
+		err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+		if err == nil {
+			c.metrics.CacheRequests.WithLabelValues(resource, "hit").Inc()
+			return &value, nil
+		}
+		// written by an older version of the models, it is overwritten below
+		c.failed(ctx, resource, "decode", err)
+	} else {
+		c.metrics.CacheRequests.WithLabelValues(resource, "miss").Inc()
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		c.failed(ctx, resource, "encode", err)
+		return value, nil
+	}
+	if err := c.backend.Set(ctx, key, buf.Bytes(), c.ttl); err != nil {
+		c.failed(ctx, resource, "write", err)
+	}
+	return value, nil
+}
+
+// cachedPage is cached for list endpoints, keyed by the query.
+func cachedPage[T any](ctx context.Context, c *Catalog, resource string, q *models.ListQuery, load func() (*models.Page[T], error)) (*models.Page[T], error) {
+	page, err := cached(ctx, c, resource, "list:"+queryKey(q), load)
+	// gob drops empty slices, but lists are always answered with an array
+	if page != nil && page.Data == nil {
+		page.Data = []T{}
+	}
+	return page, err
+}
+
+func itemKey(id uint) string {
+	return fmt.Sprintf("id:%d", id)
+}
+
+// queryKey serializes q, with the filters in a fixed order since they are
+// parsed from a map.
+func queryKey(q *models.ListQuery) string {
+	sorted := *q
+	sorted.Filters = slices.Clone(q.Filters)
+	slices.SortFunc(sorted.Filters, func(a, b models.Filter) int {
+		return strings.Compare(a.Column, b.Column)
+	})
+	key, _ := json.Marshal(sorted)
+	return string(key)
+}
+
+// invalidate starts a new generation of the catalog unless the write failed,
+// and returns the error of the write. The write is done by then, so the
+// generation is incremented even if the request was cancelled meanwhile.
+func (c *Catalog) invalidate(ctx context.Context, err error) error {
+	if err != nil {
+		return err
+	}
+	if _, err := c.backend.Incr(context.WithoutCancel(ctx), generationKey); err != nil {
+		slog.ErrorContext(ctx, "Failed to invalidate the catalog cache, it may be stale until it expires", "error", err)
+		return nil
+	}
+	c.metrics.CacheInvalidations.Inc()
+	return nil
+}
+
+func (c *Catalog) failed(ctx context.Context, resource, operation string, err error) {
+	c.metrics.CacheRequests.WithLabelValues(resource, "error").Inc()
+	slog.WarnContext(ctx, "Catalog cache failed", "resource", resource, "operation", operation, "error", err)
+}
+
+// Foods caches the reads of store.
+func (c *Catalog) Foods(store models.FoodStore) models.FoodStore {
+	return &foods{store, c}
+}
+
+// Sides caches the reads of store.
+func (c *Catalog) Sides(store models.SidesStore) models.SidesStore {
+	return &sides{store, c}
+}
+
+// MealTypes caches the reads of store.
+func (c *Catalog) MealTypes(store models.MealTypeStore) models.MealTypeStore {
+	return &mealTypes{store, c}
+}
+
+// Reviews invalidates the catalog when store adds a review, which changes
+// the ratings of a food and side.
+func (c *Catalog) Reviews(store models.ReviewStore) models.ReviewStore {
+	return &reviews{store, c}
+}
+
+// Imports invalidates the catalog when store imports rows.
+func (c *Catalog) Imports(store models.ImportStore) models.ImportStore {
+	return &imports{store, c}
+}
+
+// Trash invalidates the catalog when store restores a row.
+func (c *Catalog) Trash(store models.TrashStore) models.TrashStore {
+	return &trash{store, c}
+}
+
+// The wrappers below implement every method of their store explicitly rather
+// than embedding it, so that a write added to a store cannot skip the
+// invalidation unnoticed.
+
+type foods struct {
+	store   models.FoodStore
+	catalog *Catalog
+}
+
+func (f *foods) CreateFood(ctx context.Context, food *models.Food) error {
+	return f.catalog.invalidate(ctx, f.store.CreateFood(ctx, food))
+}
+
+func (f *foods) GetFood(ctx context.Context, id uint) (*models.Food, error) {
+	return cached(ctx, f.catalog, "food", itemKey(id), func() (*models.Food, error) {
+		return f.store.GetFood(ctx, id)
+	})
+}
+
+func (f *foods) GetFoods(ctx context.Context, q *models.ListQuery) (*models.Page[models.Food], error) {
+	return cachedPage(ctx, f.catalog, "food", q, func() (*models.Page[models.Food], error) {
+		return f.store.GetFoods(ctx, q)
+	})
+}
+
+func (f *foods) UpdateFood(ctx context.Context, id uint, version models.Version, food *models.Food) error {
+	return f.catalog.invalidate(ctx, f.store.UpdateFood(ctx, id, version, food))
+}
+
+func (f *foods) PatchFood(ctx context.Context, id uint, version models.Version, changes map[string]interface{}) (*models.Food, error) {
+	food, err := f.store.PatchFood(ctx, id, version, changes)
+	return food, f.catalog.invalidate(ctx, err)
+}
+
+func (f *foods) DeleteFood(ctx context.Context, id uint, version models.Version) error {
+	return f.catalog.invalidate(ctx, f.store.DeleteFood(ctx, id, version))
+}
+
+func (f *foods) SetFoodImage(ctx context.Context, id uint, imageKey, thumbnailKey string) error {
+	return f.catalog.invalidate(ctx, f.store.SetFoodImage(ctx, id, imageKey, thumbnailKey))
+}
+
+func (f *foods) ExportFoods(ctx context.Context, fn func(*models.FoodExport) error) error {
+	return f.store.ExportFoods(ctx, fn)
+}
+
+type sides struct {
+	store   models.SidesStore
+	catalog *Catalog
+}
+
+func (s *sides) CreateSides(ctx context.Context, side *models.Sides) error {
+	return s.catalog.invalidate(ctx, s.store.CreateSides(ctx, side))
+}
+
+func (s *sides) GetSide(ctx context.Context, id uint) (*models.Sides, error) {
+	return cached(ctx, s.catalog, "sides", itemKey(id), func() (*models.Sides, error) {
+		return s.store.GetSide(ctx, id)
+	})
+}
+
+func (s *sides) GetSides(ctx context.Context, q *models.ListQuery) (*models.Page[models.Sides], error) {
+	return cachedPage(ctx, s.catalog, "sides", q, func() (*models.Page[models.Sides], error) {
+		return s.store.GetSides(ctx, q)
+	})
+}
+
+func (s *sides) UpdateSides(ctx context.Context, id uint, version models.Version, side *models.Sides) error {
+	return s.catalog.invalidate(ctx, s.store.UpdateSides(ctx, id, version, side))
+}
+
+func (s *sides) PatchSides(ctx context.Context, id uint, version models.Version, changes map[string]interface{}) (*models.Sides, error) {
+	side, err := s.store.PatchSides(ctx, id, version, changes)
+	return side, s.catalog.invalidate(ctx, err)
+}
+
+func (s *sides) DeleteSides(ctx context.Context, id uint, version models.Version) error {
+	return s.catalog.invalidate(ctx, s.store.DeleteSides(ctx, id, version))
+}
+
+func (s *sides) SetSidesImage(ctx context.Context, id uint, imageKey, thumbnailKey string) error {
+	return s.catalog.invalidate(ctx, s.store.SetSidesImage(ctx, id, imageKey, thumbnailKey))
+}
+
+type mealTypes struct {
+	store   models.MealTypeStore
+	catalog *Catalog
+}
+
+func (m *mealTypes) CreateMealType(ctx context.Context, mealType *models.MealType) error {
+	return m.catalog.invalidate(ctx, m.store.CreateMealType(ctx, mealType))
+}
+
+func (m *mealTypes) GetMealType(ctx context.Context, id uint) (*models.MealType, error) {
+	return cached(ctx, m.catalog, "mealtype", itemKey(id), func() (*models.MealType, error) {
+		return m.store.GetMealType(ctx, id)
+	})
+}
+
+func (m *mealTypes) GetMealTypes(ctx context.Context, q *models.ListQuery) (*models.Page[models.MealType], error) {
+	return cachedPage(ctx, m.catalog, "mealtype", q, func() (*models.Page[models.MealType], error) {
+		return m.store.GetMealTypes(ctx, q)
+	})
+}
+
+func (m *mealTypes) UpdateMealType(ctx context.Context, id uint, version models.Version, mealType *models.MealType) error {
+	return m.catalog.invalidate(ctx, m.store.UpdateMealType(ctx, id, version, mealType))
+}
+
+func (m *mealTypes) PatchMealType(ctx context.Context, id uint, version models.Version, changes map[string]interface{}) (*models.MealType, error) {
+	mealType, err := m.store.PatchMealType(ctx, id, version, changes)
+	return mealType, m.catalog.invalidate(ctx, err)
+}
+
+func (m *mealTypes) DeleteMealType(ctx context.Context, id uint, version models.Version) error {
+	return m.catalog.invalidate(ctx, m.store.DeleteMealType(ctx, id, version))
+}
+
+type reviews struct {
+	store   models.ReviewStore
+	catalog *Catalog
+}
+
+func (r *reviews) CreateReview(ctx context.Context, userID, reservationID uint, review *models.Review) error {
+	return r.catalog.invalidate(ctx, r.store.CreateReview(ctx, userID, reservationID, review))
+}
+
+func (r *reviews) GetFoodReviews(ctx context.Context, foodID uint) ([]models.Review, error) {
+	return r.store.GetFoodReviews(ctx, foodID)
+}
+
+func (r *reviews) GetSideReviews(ctx context.Context, sideID uint) ([]models.Review, error) {
+	return r.store.GetSideReviews(ctx, sideID)
+}
+
+func (r *reviews) GetReviews(ctx context.Context, hidden *bool) ([]models.Review, error) {
+	return r.store.GetReviews(ctx, hidden)
+}
+
+// SetReviewHidden leaves the ratings as they are.
+func (r *reviews) SetReviewHidden(ctx context.Context, id uint, version models.Version, hidden bool) error {
+	return r.store.SetReviewHidden(ctx, id, version, hidden)
+}
+
+type imports struct {
+	store   models.ImportStore
+	catalog *Catalog
+}
+
+func (i *imports) Import(ctx context.Context, resource string, r io.Reader, dryRun bool) (*models.ImportReport, error) {
+	report, err := i.store.Import(ctx, resource, r, dryRun)
+	if dryRun {
+		return report, err
+	}
+	return report, i.catalog.invalidate(ctx, err)
+}
+
+type trash struct {
+	store   models.TrashStore
+	catalog *Catalog
+}
+
+func (t *trash) ListTrash(ctx context.Context, resource string, q *models.ListQuery) (*models.Page[models.TrashItem], error) {
+	return t.store.ListTrash(ctx, resource, q)
+}
+
+func (t *trash) Restore(ctx context.Context, resource string, id uint) error {
+	return t.catalog.invalidate(ctx, t.store.Restore(ctx, resource, id))
+}
+
+// Purge only removes rows that are already hidden from the catalog.
+func (t *trash) Purge(ctx context.Context, resource string, before time.Time) (int64, error) {
+	return t.store.Purge(ctx, resource, before)
+}
+
+func (t *trash) PurgeAll(ctx context.Context, before time.Time) (map[string]int64, error) {
+	return t.store.PurgeAll(ctx, before)
+}
+
+var (
+	_ models.FoodStore     = (*foods)(nil)
+	_ models.SidesStore    = (*sides)(nil)
+	_ models.MealTypeStore = (*mealTypes)(nil)
+	_ models.ReviewStore   = (*reviews)(nil)
+	_ models.ImportStore   = (*imports)(nil)
+	_ models.TrashStore    = (*trash)(nil)
+)
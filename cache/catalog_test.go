@@ -0,0 +1,129 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/app"
+	"github.com/Hamedblue1381/restaurant-reserve/config"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/routers"
+	"github.com/Hamedblue1381/restaurant-reserve/storage"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCatalogInvalidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	backends := map[string]config.CacheConfig{
+		"memory": {Backend: config.CacheMemory, TTL: config.Duration(time.Hour), Size: 100},
+		"redis":  {Backend: config.CacheRedis, TTL: config.Duration(time.Hour), RedisURL: "redis://" + server.Addr()},
+	}
+	for name, cacheConfig := range backends {
+		t.Run(name, func(t *testing.T) {
+			testInvalidation(t, cacheConfig)
+		})
+	}
+}
+
+// testInvalidation adds rows behind the API's back, which the cached lists
+// must not show until a write through the API invalidates them.
+func testInvalidation(t *testing.T, cacheConfig config.CacheConfig) {
+	db := config.SetupDBConnection(config.DatabaseConfig{Driver: config.DriverSQLiteMemory})
+	db.Logger = logger.Discard
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	_, err := models.NewImportHandler(db).Import(context.Background(), models.ImportMenus, strings.NewReader("meal_type,category,food,quantity\nLunch,Main,Kebab,1\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Cache: cacheConfig}
+	cfg.Auth.JWTSecret = "secret"
+	cfg.Auth.TokenTTL = config.Duration(time.Hour)
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := app.New(cfg, db, blobs)
+	router := routers.UseRouter(a)
+	token, err := a.Tokens.GenerateToken("admin@example.com", 1, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	lists := []string{"/api/v1/food", "/api/v1/sides", "/api/v1/mealtype"}
+	counts := func() map[string]int {
+		result := make(map[string]int, len(lists))
+		for _, path := range lists {
+			var page struct{ Data []json.RawMessage }
+			w := send(http.MethodGet, path, "")
+			if err := json.Unmarshal(w.Body.Bytes(), &page); w.Code != http.StatusOK || err != nil {
+				t.Fatalf("GET %s = %d %s", path, w.Code, w.Body)
+			}
+			result[path] = len(page.Data)
+		}
+		return result
+	}
+
+	writes := []struct{ path, body string }{
+		{"/api/v1/food", `{"name":"Soup","CategoryID":1,"MealTypeID":1}`},
+		{"/api/v1/sides", `{"name":"Salad"}`},
+		{"/api/v1/mealtype", `{"name":"Dinner"}`},
+	}
+	for i, write := range writes {
+		before := counts()
+		insertBehind(t, db, i)
+		if got := counts(); fmt.Sprint(got) != fmt.Sprint(before) {
+			t.Fatalf("lists changed without a write through the API: %v, cached %v", got, before)
+		}
+
+		if w := send(http.MethodPost, write.path, write.body); w.Code != http.StatusCreated {
+			t.Fatalf("POST %s = %d %s", write.path, w.Code, w.Body)
+		}
+		after := counts()
+		for _, path := range lists {
+			// the list written to also gains the row created by the API
+			want := before[path] + 1
+			if path == write.path {
+				want++
+			}
+			if after[path] != want {
+				t.Errorf("after POST %s, GET %s has %d rows, want %d", write.path, path, after[path], want)
+			}
+		}
+	}
+}
+
+// insertBehind adds a food, side dish and meal type directly to db.
+func insertBehind(t *testing.T, db *gorm.DB, i int) {
+	t.Helper()
+	for _, row := range []interface{}{
+		&models.Food{Name: fmt.Sprint("Food ", i), CategoryID: 1, MealTypeID: 1},
+		&models.Sides{Name: fmt.Sprint("Side ", i)},
+		&models.MealType{Name: fmt.Sprint("Meal type ", i)},
+	} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is a least recently used cache local to the process. Counters are
+// kept apart from the values so that they are never evicted.
+type Memory struct {
+	mu       sync.Mutex
+	size     int
+	order    *list.List
+	entries  map[string]*list.Element
+	counters map[string]int64
+	now      func() time.Time
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemory creates a cache holding at most size values.
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:     size,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, size),
+		counters: make(map[string]int64),
+		now:      time.Now,
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if !m.now().Before(entry.expires) {
+		m.remove(element)
+		return nil, false, nil
+	}
+	m.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expires := m.now().Add(ttl)
+	if element, ok := m.entries[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		entry.value, entry.expires = value, expires
+		m.order.MoveToFront(element)
+		return nil
+	}
+	m.entries[key] = m.order.PushFront(&memoryEntry{key, value, expires})
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+func (m *Memory) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.entries, element.Value.(*memoryEntry).key)
+}
+
+func (m *Memory) Incr(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[key]++
+	return m.counters[key], nil
+}
+
+func (m *Memory) Counter(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[key], nil
+}
+
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps the cache in a server speaking the Redis protocol, so that it
+// is shared by every instance of the API.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects lazily to the server at url, such as
+// redis://:password@localhost:6379/0. Unless the url sets dial_timeout,
+// read_timeout or write_timeout, they are kept short: an unreachable cache
+// must not hold up the requests it is meant to speed up.
+func NewRedis(url string) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if options.DialTimeout == 0 {
+		options.DialTimeout = time.Second
+	}
+	if options.ReadTimeout == 0 {
+		options.ReadTimeout = 500 * time.Millisecond
+	}
+	if options.WriteTimeout == 0 {
+		options.WriteTimeout = 500 * time.Millisecond
+	}
+	return &Redis{redis.NewClient(options)}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (r *Redis) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *Redis) Counter(ctx context.Context, key string) (int64, error) {
+	value, err := r.client.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return value, err
+}
+
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
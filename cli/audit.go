@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+func runAuditVerify(ctx context.Context, db *gorm.DB, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	verification, err := models.NewAuditHandler(db).VerifyAudit(ctx)
+	if err != nil {
+		return err
+	}
+	if !verification.Valid {
+		return fmt.Errorf("audit log is broken at entry %d: %s", *verification.BrokenAt, verification.Reason)
+	}
+	fmt.Printf("audit log is intact: %d entries, head %s\n", verification.Entries, verification.Head)
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+// command is a subcommand of the main binary.
+type command struct {
+	usage string
+	run   func(ctx context.Context, db *gorm.DB, args []string) error
+}
+
+var commands = map[string]command{
+	"import":         {"import [-dry-run] <users|foods|sides|menus> <file.csv>", runImport},
+	"migrate":        {"migrate up | down [-steps n] | status", runMigrate},
+	"create-admin":   {"create-admin -name <name> -email <email> [-telephone <telephone>] < password", runCreateAdmin},
+	"reset-password": {"reset-password <email> < password", runResetPassword},
+	"seed":           {"seed", runSeed},
+	"blacklist":      {"blacklist list | lift <email>", runBlacklist},
+	"mark-paid":      {"mark-paid -user <user id> | <reservation id>...", runMarkPaid},
+	"purge":          {"purge [-days n] [" + strings.Join(models.TrashResources(), "|") + "]", runPurge},
+	"audit-verify":   {"audit-verify", runAuditVerify},
+}
+
+// IsCommand reports whether name is a known subcommand.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by args[0] with the remaining arguments.
+// Cancelling ctx aborts the queries of the command.
+func Run(ctx context.Context, db *gorm.DB, args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	if err := cmd.run(ctx, db, args[1:]); err != nil {
+		if err == errUsage {
+			fmt.Fprintln(os.Stderr, "usage:", os.Args[0], cmd.usage)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+var errUsage = errors.New("invalid arguments")
+
+func runImport(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "validate the file without writing anything")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 2 {
+		return errUsage
+	}
+
+	file, err := os.Open(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := models.NewImportHandler(db).Import(ctx, flags.Arg(0), file, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: %d rows, %d created, %d updated\n", report.Resource, report.Rows, report.Created, report.Updated)
+	for _, e := range report.Errors {
+		if e.Field != "" {
+			fmt.Printf("row %d: %s: %s\n", e.Row, e.Field, e.Message)
+		} else {
+			fmt.Printf("row %d: %s\n", e.Row, e.Message)
+		}
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		return fmt.Errorf("%d invalid rows, nothing was imported", len(report.Errors))
+	case report.DryRun:
+		fmt.Println("dry run, nothing was imported")
+	}
+	return nil
+}
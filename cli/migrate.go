@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/migrations"
+	"gorm.io/gorm"
+)
+
+func runMigrate(ctx context.Context, db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return errUsage
+		}
+		applied, err := migrations.Up(db.WithContext(ctx))
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to revert")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 0 || *steps < 1 {
+			return errUsage
+		}
+		reverted, err := migrations.Down(db.WithContext(ctx), *steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		if len(args) != 1 {
+			return errUsage
+		}
+		states, err := migrations.Status(db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			status := "pending"
+			if state.AppliedAt != nil {
+				status = "applied " + state.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", state.Version, state.Name, status)
+		}
+		return nil
+	}
+	return errUsage
+}
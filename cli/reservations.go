@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+func runMarkPaid(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("mark-paid", flag.ContinueOnError)
+	userID := flags.Uint("user", 0, "mark every unpaid reservation of this user id")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	reservations := models.NewReservationHandler(db)
+	var count int64
+	var err error
+	switch {
+	case *userID != 0 && flags.NArg() == 0:
+		count, err = reservations.MarkUserPaid(ctx, *userID)
+	case *userID == 0 && flags.NArg() > 0:
+		ids := make([]uint, flags.NArg())
+		for i, arg := range flags.Args() {
+			id, err := strconv.ParseUint(arg, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid reservation id %q", arg)
+			}
+			ids[i] = uint(id)
+		}
+		count, err = reservations.MarkPaid(ctx, ids)
+	default:
+		return errUsage
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("marked %d reservations as paid\n", count)
+	return nil
+}
+
+func runPurge(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("purge", flag.ContinueOnError)
+	days := flags.Int("days", 30, "only purge rows deleted more than this many days ago")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 || *days < 0 {
+		return errUsage
+	}
+
+	trash := models.NewTrashHandler(db)
+	before := time.Now().AddDate(0, 0, -*days)
+	if flags.NArg() == 1 {
+		count, err := trash.Purge(ctx, flags.Arg(0), before)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: purged %d rows\n", flags.Arg(0), count)
+		return nil
+	}
+
+	purged, err := trash.PurgeAll(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, resource := range models.TrashResources() {
+		fmt.Printf("%s: purged %d rows\n", resource, purged[resource])
+	}
+	return nil
+}
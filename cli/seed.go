@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+//go:embed seed/*.csv
+var seedFiles embed.FS
+
+// seedImports lists the demo data files in the order they are imported.
+var seedImports = []struct {
+	resource string
+	file     string
+}{
+	{models.ImportMenus, "seed/menus.csv"},
+	{models.ImportSides, "seed/sides.csv"},
+}
+
+// runSeed loads the demo menu through the CSV importer, so running it again
+// updates the rows instead of duplicating them.
+func runSeed(ctx context.Context, db *gorm.DB, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	importer := models.NewImportHandler(db)
+	for _, seed := range seedImports {
+		data, err := seedFiles.ReadFile(seed.file)
+		if err != nil {
+			return err
+		}
+		report, err := importer.Import(ctx, seed.resource, bytes.NewReader(data), false)
+		if err != nil {
+			return err
+		}
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("%s: %d invalid rows in seed data", seed.resource, len(report.Errors))
+		}
+		fmt.Printf("%s: %d created, %d updated\n", report.Resource, report.Created, report.Updated)
+	}
+	return nil
+}
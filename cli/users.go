@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+// readPassword reads a password from the first line of standard input so it
+// never shows up in the process list or shell history.
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	return password, nil
+}
+
+func runCreateAdmin(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	name := flags.String("name", "", "name of the admin")
+	email := flags.String("email", "", "email the admin logs in with")
+	telephone := flags.String("telephone", "", "telephone number of the admin")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || *name == "" || *email == "" {
+		return errUsage
+	}
+
+	users := models.NewUserHandler(db)
+	if _, err := users.GetUserByEmail(ctx, *email); err == nil {
+		return fmt.Errorf("a user with email %s already exists", *email)
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		return err
+	}
+
+	admin := models.User{Name: *name, Email: *email, Telephone: *telephone, Role: "admin", Password: password}
+	if err := users.CreateUser(ctx, &admin); err != nil {
+		return err
+	}
+	fmt.Printf("created admin %s with id %d\n", admin.Email, admin.ID)
+	return nil
+}
+
+func runResetPassword(ctx context.Context, db *gorm.DB, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	users := models.NewUserHandler(db)
+	user, err := users.GetUserByEmail(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		return err
+	}
+	if err := users.ResetPassword(ctx, user.ID, password); err != nil {
+		return err
+	}
+	fmt.Printf("password of %s was reset\n", user.Email)
+	return nil
+}
+
+func runBlacklist(ctx context.Context, db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			return errUsage
+		}
+		entries, err := models.NewReservationHandler(db).ListBlacklisted(ctx)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Printf("%d\t%s\t%s\t%d unpaid\n", entry.UserID, entry.Name, entry.Email, entry.Unpaid)
+		}
+		if len(entries) == 0 {
+			fmt.Println("no blacklisted users")
+		}
+		return nil
+	case "lift":
+		if len(args) != 2 {
+			return errUsage
+		}
+		users := models.NewUserHandler(db)
+		user, err := users.GetUserByEmail(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if err := users.LiftBlacklist(ctx, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("lifted the blacklist of %s\n", user.Email)
+		return nil
+	}
+	return errUsage
+}
@@ -0,0 +1,471 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/redis/go-redis/v9"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the complete application configuration.
+//
+// Values are loaded from the environment (including a .env file in the
+// working directory), then from an optional YAML or TOML file, then from
+// command line flags. Each source overrides the previous one, so flags always
+// win. Empty environment variables are treated as unset.
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Storage  StorageConfig  `yaml:"storage" toml:"storage"`
+	Cache    CacheConfig    `yaml:"cache" toml:"cache"`
+	Tracing  TracingConfig  `yaml:"tracing" toml:"tracing"`
+	Log      LogConfig      `yaml:"log" toml:"log"`
+}
+
+type ServerConfig struct {
+	Port    int    `yaml:"port" toml:"port"`
+	BaseURL string `yaml:"base_url" toml:"base_url"`
+	// IdempotencyTTL is how long the response to an Idempotency-Key is kept
+	IdempotencyTTL Duration `yaml:"idempotency_ttl" toml:"idempotency_ttl"`
+	// DrainDelay is how long /readyz fails before the server stops accepting
+	// connections on shutdown, so that load balancers stop routing to it
+	DrainDelay Duration `yaml:"drain_delay" toml:"drain_delay"`
+	// HealthTimeout bounds each dependency check of /readyz
+	HealthTimeout Duration `yaml:"health_timeout" toml:"health_timeout"`
+}
+
+// Database drivers. The sqlite-memory driver is an in-memory SQLite database
+// that is migrated on startup and discarded on exit. It is not a separate
+// implementation of the stores, so it runs the same model code as the others.
+const (
+	DriverPostgres     = "postgres"
+	DriverSQLite       = "sqlite"
+	DriverSQLiteMemory = "sqlite-memory"
+)
+
+type DatabaseConfig struct {
+	Driver string `yaml:"driver" toml:"driver"`
+	// DSN is a PostgreSQL connection string or the path of the SQLite file
+	DSN string `yaml:"dsn" toml:"dsn"`
+	// AutoMigrate lets GORM create the tables at startup, for local development
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate"`
+	// QueryTimeout bounds the queries of a request, unless RouteTimeouts has
+	// an entry for its route such as "GET /api/v1/export/users"
+	QueryTimeout  Duration            `yaml:"query_timeout" toml:"query_timeout"`
+	RouteTimeouts map[string]Duration `yaml:"route_timeouts" toml:"route_timeouts"`
+	// TrashRetention is how long deleted rows are kept before the API may
+	// purge them
+	TrashRetention Duration `yaml:"trash_retention" toml:"trash_retention"`
+}
+
+// Timeouts returns RouteTimeouts as plain durations.
+func (d DatabaseConfig) Timeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(d.RouteTimeouts))
+	for route, timeout := range d.RouteTimeouts {
+		timeouts[route] = time.Duration(timeout)
+	}
+	return timeouts
+}
+
+type AuthConfig struct {
+	JWTSecret string   `yaml:"jwt_secret" toml:"jwt_secret"`
+	TokenTTL  Duration `yaml:"token_ttl" toml:"token_ttl"`
+}
+
+type StorageConfig struct {
+	Dir string `yaml:"dir" toml:"dir"`
+}
+
+// Cache backends. The memory cache is local to each process, Redis shares
+// it between the instances of the API.
+const (
+	CacheNone   = "none"
+	CacheMemory = "memory"
+	CacheRedis  = "redis"
+)
+
+type CacheConfig struct {
+	Backend string `yaml:"backend" toml:"backend"`
+	// TTL bounds how long a read of the catalog is cached. Writes through
+	// the API invalidate it at once, other writes are seen once it expires.
+	TTL Duration `yaml:"ttl" toml:"ttl"`
+	// Size is the number of reads the memory cache holds
+	Size int `yaml:"size" toml:"size"`
+	// RedisURL is the server of the redis cache, such as
+	// redis://localhost:6379/0
+	RedisURL string `yaml:"redis_url" toml:"redis_url"`
+}
+
+// Trace exporters. Spans are written as JSON lines to standard output or to
+// a file for offline use, or sent to an OpenTelemetry collector over
+// OTLP/HTTP.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Endpoint is the OTLP/HTTP endpoint, such as http://localhost:4318. When
+	// empty the standard OTEL_EXPORTER_OTLP_* variables apply.
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// File is where the file exporter appends spans
+	File        string `yaml:"file" toml:"file"`
+	ServiceName string `yaml:"service_name" toml:"service_name"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error. Debug adds the request headers to
+	// the request log, with credentials redacted.
+	Level slog.Level `yaml:"level" toml:"level"`
+}
+
+// Duration is a time.Duration written as a string such as "24h" in config
+// files.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	value, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(value)
+	return nil
+}
+
+// Options are the command line options that are not part of the
+// configuration itself.
+type Options struct {
+	File        string
+	PrintConfig bool
+	// Args are the arguments left after the flags, such as a subcommand
+	Args []string
+}
+
+func defaults() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:           8080,
+			IdempotencyTTL: Duration(24 * time.Hour),
+			DrainDelay:     Duration(5 * time.Second),
+			HealthTimeout:  Duration(2 * time.Second),
+		},
+		Database: DatabaseConfig{
+			Driver:       DriverPostgres,
+			QueryTimeout: Duration(10 * time.Second),
+			// exports, imports and reports work through whole tables
+			RouteTimeouts: map[string]Duration{
+				"GET /api/v1/export/reservations": Duration(2 * time.Minute),
+				"GET /api/v1/export/users":        Duration(2 * time.Minute),
+				"GET /api/v1/export/foods":        Duration(2 * time.Minute),
+				"POST /api/v1/import/:resource":   Duration(2 * time.Minute),
+				"GET /api/v1/reports/production":  Duration(time.Minute),
+				"DELETE /api/v1/trash":            Duration(2 * time.Minute),
+				"DELETE /api/v1/trash/:resource":  Duration(2 * time.Minute),
+			},
+			TrashRetention: Duration(30 * 24 * time.Hour),
+		},
+		Auth:    AuthConfig{TokenTTL: Duration(24 * time.Hour)},
+		Storage: StorageConfig{Dir: "uploads"},
+		Cache:   CacheConfig{Backend: CacheMemory, TTL: Duration(time.Minute), Size: 1000},
+		Tracing: TracingConfig{Exporter: ExporterNone, File: "traces.jsonl", ServiceName: "restaurant-reserve"},
+	}
+}
+
+// envVars maps environment variables to the setting they configure.
+var envVars = []struct {
+	name string
+	set  func(c *Config, value string) error
+}{
+	{"PORT", func(c *Config, v string) (err error) { c.Server.Port, err = strconv.Atoi(v); return }},
+	{"BASE_URL", func(c *Config, v string) error { c.Server.BaseURL = v; return nil }},
+	{"IDEMPOTENCY_TTL", func(c *Config, v string) error { return c.Server.IdempotencyTTL.UnmarshalText([]byte(v)) }},
+	{"DRAIN_DELAY", func(c *Config, v string) error { return c.Server.DrainDelay.UnmarshalText([]byte(v)) }},
+	{"HEALTH_TIMEOUT", func(c *Config, v string) error { return c.Server.HealthTimeout.UnmarshalText([]byte(v)) }},
+	{"DB_DRIVER", func(c *Config, v string) error { c.Database.Driver = v; return nil }},
+	{"DB_CONN", func(c *Config, v string) error { c.Database.DSN = v; return nil }},
+	{"DB_AUTO_MIGRATE", func(c *Config, v string) (err error) { c.Database.AutoMigrate, err = strconv.ParseBool(v); return }},
+	{"DB_QUERY_TIMEOUT", func(c *Config, v string) error { return c.Database.QueryTimeout.UnmarshalText([]byte(v)) }},
+	{"TRASH_RETENTION", func(c *Config, v string) error { return c.Database.TrashRetention.UnmarshalText([]byte(v)) }},
+	{"JWT_SECRET", func(c *Config, v string) error { c.Auth.JWTSecret = v; return nil }},
+	{"JWT_TTL", func(c *Config, v string) error { return c.Auth.TokenTTL.UnmarshalText([]byte(v)) }},
+	{"STORAGE_DIR", func(c *Config, v string) error { c.Storage.Dir = v; return nil }},
+	{"CACHE_BACKEND", func(c *Config, v string) error { c.Cache.Backend = v; return nil }},
+	{"CACHE_TTL", func(c *Config, v string) error { return c.Cache.TTL.UnmarshalText([]byte(v)) }},
+	{"CACHE_SIZE", func(c *Config, v string) (err error) { c.Cache.Size, err = strconv.Atoi(v); return }},
+	{"REDIS_URL", func(c *Config, v string) error { c.Cache.RedisURL = v; return nil }},
+	{"TRACE_EXPORTER", func(c *Config, v string) error { c.Tracing.Exporter = v; return nil }},
+	{"TRACE_ENDPOINT", func(c *Config, v string) error { c.Tracing.Endpoint = v; return nil }},
+	{"TRACE_FILE", func(c *Config, v string) error { c.Tracing.File = v; return nil }},
+	{"OTEL_SERVICE_NAME", func(c *Config, v string) error { c.Tracing.ServiceName = v; return nil }},
+	{"LOG_LEVEL", func(c *Config, v string) error { return c.Log.Level.UnmarshalText([]byte(v)) }},
+}
+
+// Load builds the configuration from the environment, the config file and
+// the command line arguments (without the program name), and validates it.
+func Load(args []string) (*Config, *Options, error) {
+	cfg := defaults()
+	opts := &Options{}
+
+	// Values are bound to a separate struct so that only the flags that were
+	// actually given override the other sources.
+	flagged := *cfg
+	flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	flags.StringVar(&opts.File, "config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	flags.BoolVar(&opts.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	flags.IntVar(&flagged.Server.Port, "port", cfg.Server.Port, "port the server listens on")
+	flags.StringVar(&flagged.Server.BaseURL, "base-url", "", "public host of the API, used in QR codes")
+	flags.TextVar(&flagged.Server.IdempotencyTTL, "idempotency-ttl", cfg.Server.IdempotencyTTL, "how long responses to idempotency keys are kept")
+	flags.TextVar(&flagged.Server.DrainDelay, "drain-delay", cfg.Server.DrainDelay, "how long readiness fails before shutting down")
+	flags.TextVar(&flagged.Server.HealthTimeout, "health-timeout", cfg.Server.HealthTimeout, "time limit of each readiness check")
+	flags.StringVar(&flagged.Database.Driver, "db-driver", cfg.Database.Driver, "database driver: postgres, sqlite or sqlite-memory")
+	flags.StringVar(&flagged.Database.DSN, "db-conn", "", "PostgreSQL connection string or SQLite file")
+	flags.BoolVar(&flagged.Database.AutoMigrate, "db-auto-migrate", false, "create tables with AutoMigrate instead of checking migrations")
+	flags.TextVar(&flagged.Database.QueryTimeout, "db-query-timeout", cfg.Database.QueryTimeout, "default time limit for the queries of a request")
+	flags.TextVar(&flagged.Database.TrashRetention, "trash-retention", cfg.Database.TrashRetention, "how long deleted rows are kept before they can be purged")
+	flags.StringVar(&flagged.Auth.JWTSecret, "jwt-secret", "", "secret used to sign tokens")
+	flags.TextVar(&flagged.Auth.TokenTTL, "jwt-ttl", cfg.Auth.TokenTTL, "lifetime of issued tokens")
+	flags.StringVar(&flagged.Storage.Dir, "storage-dir", cfg.Storage.Dir, "directory uploaded images are stored in")
+	flags.StringVar(&flagged.Cache.Backend, "cache-backend", cfg.Cache.Backend, "catalog cache: none, memory or redis")
+	flags.TextVar(&flagged.Cache.TTL, "cache-ttl", cfg.Cache.TTL, "how long catalog reads are cached")
+	flags.IntVar(&flagged.Cache.Size, "cache-size", cfg.Cache.Size, "number of reads the memory cache holds")
+	flags.StringVar(&flagged.Cache.RedisURL, "redis-url", "", "server of the redis cache")
+	flags.StringVar(&flagged.Tracing.Exporter, "trace-exporter", cfg.Tracing.Exporter, "trace exporter: none, stdout, file or otlp")
+	flags.StringVar(&flagged.Tracing.Endpoint, "trace-endpoint", "", "OTLP/HTTP endpoint traces are sent to")
+	flags.StringVar(&flagged.Tracing.File, "trace-file", cfg.Tracing.File, "file the file exporter appends traces to")
+	flags.TextVar(&flagged.Log.Level, "log-level", cfg.Log.Level, "minimum level logged: debug, info, warn or error")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	opts.Args = flags.Args()
+
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+	for _, env := range envVars {
+		if value := os.Getenv(env.name); value != "" {
+			if err := env.set(cfg, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", env.name, err)
+			}
+		}
+	}
+
+	if opts.File == "" {
+		opts.File = os.Getenv("CONFIG_FILE")
+	}
+	if opts.File != "" {
+		if err := loadFile(cfg, opts.File); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = flagged.Server.Port
+		case "base-url":
+			cfg.Server.BaseURL = flagged.Server.BaseURL
+		case "idempotency-ttl":
+			cfg.Server.IdempotencyTTL = flagged.Server.IdempotencyTTL
+		case "drain-delay":
+			cfg.Server.DrainDelay = flagged.Server.DrainDelay
+		case "health-timeout":
+			cfg.Server.HealthTimeout = flagged.Server.HealthTimeout
+		case "db-driver":
+			cfg.Database.Driver = flagged.Database.Driver
+		case "db-conn":
+			cfg.Database.DSN = flagged.Database.DSN
+		case "db-auto-migrate":
+			cfg.Database.AutoMigrate = flagged.Database.AutoMigrate
+		case "db-query-timeout":
+			cfg.Database.QueryTimeout = flagged.Database.QueryTimeout
+		case "trash-retention":
+			cfg.Database.TrashRetention = flagged.Database.TrashRetention
+		case "jwt-secret":
+			cfg.Auth.JWTSecret = flagged.Auth.JWTSecret
+		case "jwt-ttl":
+			cfg.Auth.TokenTTL = flagged.Auth.TokenTTL
+		case "storage-dir":
+			cfg.Storage.Dir = flagged.Storage.Dir
+		case "cache-backend":
+			cfg.Cache.Backend = flagged.Cache.Backend
+		case "cache-ttl":
+			cfg.Cache.TTL = flagged.Cache.TTL
+		case "cache-size":
+			cfg.Cache.Size = flagged.Cache.Size
+		case "redis-url":
+			cfg.Cache.RedisURL = flagged.Cache.RedisURL
+		case "trace-exporter":
+			cfg.Tracing.Exporter = flagged.Tracing.Exporter
+		case "trace-endpoint":
+			cfg.Tracing.Endpoint = flagged.Tracing.Endpoint
+		case "trace-file":
+			cfg.Tracing.File = flagged.Tracing.File
+		case "log-level":
+			cfg.Log.Level = flagged.Log.Level
+		}
+	})
+
+	return cfg, opts, cfg.Validate()
+}
+
+// loadFile merges the settings present in a YAML or TOML file into cfg.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server port %d is out of range", c.Server.Port))
+	}
+	if c.Server.IdempotencyTTL <= 0 {
+		errs = append(errs, errors.New("idempotency ttl must be positive"))
+	}
+	if c.Server.DrainDelay < 0 {
+		errs = append(errs, errors.New("drain delay must not be negative"))
+	}
+	if c.Server.HealthTimeout <= 0 {
+		errs = append(errs, errors.New("health timeout must be positive"))
+	}
+	switch c.Database.Driver {
+	case DriverPostgres:
+		if c.Database.DSN == "" {
+			errs = append(errs, errors.New("database dsn is required (DB_CONN)"))
+		} else if _, err := pgconn.ParseConfig(c.Database.DSN); err != nil {
+			errs = append(errs, fmt.Errorf("invalid database dsn: %s", redactDSN(err.Error())))
+		}
+	case DriverSQLite:
+		if c.Database.DSN == "" {
+			errs = append(errs, errors.New("database dsn is required (DB_CONN), use a file path or :memory:"))
+		}
+	case DriverSQLiteMemory:
+	default:
+		errs = append(errs, fmt.Errorf("unknown database driver %q", c.Database.Driver))
+	}
+	if c.Database.QueryTimeout <= 0 {
+		errs = append(errs, errors.New("database query timeout must be positive"))
+	}
+	for route, timeout := range c.Database.RouteTimeouts {
+		if !routeKey.MatchString(route) {
+			errs = append(errs, fmt.Errorf("route timeout %q must look like \"GET /api/v1/path\"", route))
+		} else if timeout <= 0 {
+			errs = append(errs, fmt.Errorf("route timeout for %q must be positive", route))
+		}
+	}
+	if c.Database.TrashRetention < 0 {
+		errs = append(errs, errors.New("trash retention must not be negative"))
+	}
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("jwt secret is required (JWT_SECRET)"))
+	}
+	if c.Auth.TokenTTL <= 0 {
+		errs = append(errs, errors.New("jwt ttl must be positive"))
+	}
+	if c.Storage.Dir == "" {
+		errs = append(errs, errors.New("storage dir is required"))
+	}
+	switch c.Cache.Backend {
+	case CacheNone:
+	case CacheMemory:
+		if c.Cache.Size < 1 {
+			errs = append(errs, errors.New("cache size must be positive"))
+		}
+	case CacheRedis:
+		if c.Cache.RedisURL == "" {
+			errs = append(errs, errors.New("redis url is required by the redis cache (REDIS_URL)"))
+		} else if _, err := redis.ParseURL(c.Cache.RedisURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid redis url: %s", redactDSN(err.Error())))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown cache backend %q", c.Cache.Backend))
+	}
+	if c.Cache.Backend != CacheNone && c.Cache.TTL <= 0 {
+		errs = append(errs, errors.New("cache ttl must be positive"))
+	}
+	switch c.Tracing.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	case ExporterFile:
+		if c.Tracing.File == "" {
+			errs = append(errs, errors.New("trace file is required by the file exporter (TRACE_FILE)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown trace exporter %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("trace service name is required"))
+	}
+	return errors.Join(errs...)
+}
+
+var routeKey = regexp.MustCompile(`^[A-Z]+ /\S*$`)
+
+var dsnPassword = regexp.MustCompile(`(password=)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// redactDSN hides the password of a connection string in URL or key/value
+// form.
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+		dsn = u.String()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}xxxxx")
+}
+
+// Redacted returns a copy of the configuration that is safe to print.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.Database.DSN = redactDSN(c.Database.DSN)
+	redacted.Cache.RedisURL = redactDSN(c.Cache.RedisURL)
+	if redacted.Auth.JWTSecret != "" {
+		redacted.Auth.JWTSecret = "xxxxx"
+	}
+	return &redacted
+}
+
+// Print writes the redacted configuration as YAML.
+func (c *Config) Print() error {
+	out := yaml.NewEncoder(os.Stdout)
+	out.SetIndent(2)
+	defer out.Close()
+	return out.Encode(c.Redacted())
+}
@@ -1,21 +1,95 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/Hamedblue1381/restaurant-reserve/logging"
+	"github.com/Hamedblue1381/restaurant-reserve/migrations"
 	"github.com/Hamedblue1381/restaurant-reserve/models"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func SetupDBConnection() *gorm.DB {
-	db, err := gorm.Open(postgres.Open(os.Getenv("DB_CONN")), &gorm.Config{})
+func SetupDBConnection(cfg DatabaseConfig) *gorm.DB {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case DriverSQLite:
+		dialector = sqlite.Open(sqliteDSN(cfg.DSN))
+	case DriverSQLiteMemory:
+		dialector = sqlite.Open(sqliteDSN(":memory:"))
+	default:
+		dialector = postgres.Open(cfg.DSN)
+	}
+
+	// translated errors let unique and foreign key violations surface as
+	// conflicts and validation errors instead of internal errors
+	db, err := gorm.Open(dialector, &gorm.Config{
+		TranslateError: true,
+		Logger:         logging.NewGormLogger(200 * time.Millisecond),
+	})
 	if err != nil {
-		log.Fatal("Failed to connect to database!")
+		fatal("Failed to connect to database", err)
+	}
+
+	if cfg.Driver != DriverPostgres {
+		// SQLite allows a single writer, and every connection to :memory: opens
+		// a new empty database, so all queries share one connection.
+		sqlDB, err := db.DB()
+		if err != nil {
+			fatal("Failed to connect to database", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	// The in-memory database starts empty on every run
+	if cfg.Driver == DriverSQLiteMemory && !cfg.AutoMigrate {
+		if _, err := migrations.Up(db); err != nil {
+			fatal("Failed to migrate the in-memory database", err)
+		}
 	}
 
-	db.AutoMigrate(&models.User{}, &models.Food{}, &models.Sides{}, &models.Category{}, &models.MealType{}, &models.Reservation{})
+	// The schema is managed by the migrations package; AutoMigrate is only
+	// kept as a shortcut for local development.
+	if cfg.AutoMigrate {
+		db.AutoMigrate(&models.User{}, &models.Food{}, &models.Sides{}, &models.Category{}, &models.MealType{}, &models.Reservation{}, &models.Review{}, &models.IdempotencyKey{}, &models.AuditEntry{})
+	}
 
 	return db
 }
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", redactDSN(err.Error()))
+	os.Exit(1)
+}
+
+// sqliteDSN enables foreign keys, which SQLite leaves off by default, so
+// constraints behave the same as on PostgreSQL.
+func sqliteDSN(dsn string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_foreign_keys=1"
+	}
+	return dsn + "?_foreign_keys=1"
+}
+
+// CheckMigrations returns an error when the database has migrations that
+// have not been applied, so the server never runs against an old schema.
+func CheckMigrations(db *gorm.DB, cfg DatabaseConfig) error {
+	if cfg.AutoMigrate {
+		slog.Info("AutoMigrate is enabled, skipping the migration check")
+		return nil
+	}
+
+	pending, err := migrations.Pending(db)
+	if err != nil {
+		return fmt.Errorf("failed to check migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database has %d pending migrations, run the migrate up command first", len(pending))
+	}
+	return nil
+}
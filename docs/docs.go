@@ -15,6 +15,163 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the recorded creates, updates and deletes with who made them, from where and the changed columns. Changes made from the command line have no actor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this time (RFC 3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries before this time (RFC 3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by the user who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete",
+                            "payment",
+                            "blacklist_lift",
+                            "password_reset",
+                            "restore",
+                            "purge"
+                        ],
+                        "type": "string",
+                        "description": "Filter by action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by table, such as foods or reservations",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by the ID of the changed row",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by request ID",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by client IP",
+                        "name": "ip",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of audit entries",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_AuditEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid time, pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/verify": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Recomputes the hash chain of the audit log. An altered entry, or one following a removed entry, breaks the chain at that entry. Keep the returned head to detect entries removed from the end.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Verify the audit log",
+                "responses": {
+                    "200": {
+                        "description": "Whether the chain is intact",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuditVerification"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/register": {
             "post": {
                 "description": "Creates a new user account with the provided details. Upon successful creation, the user can log in with their credentials.",
@@ -49,13 +206,13 @@ const docTemplate = `{
                     "400": {
                         "description": "The request was formatted incorrectly or missing required fields.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
                         "description": "Internal server error, unable to process the request.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
@@ -95,166 +252,311 @@ const docTemplate = `{
                     "400": {
                         "description": "The request was formatted incorrectly or missing required fields.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "401": {
                         "description": "Authentication failed due to invalid login credentials.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "The specified user was not found in the system.",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
                         "description": "Internal server error, unable to process the request.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/food": {
+        "/export/foods": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves a list of all foods in the system.",
+                "description": "Streams all foods with their category, meal type and rating as a CSV or Excel file.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "food"
+                    "export"
+                ],
+                "summary": "Export foods",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get All Foods",
                 "responses": {
                     "200": {
-                        "description": "An array of food objects.",
+                        "description": "The exported foods",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Food"
-                            }
+                            "type": "file"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while fetching foods.",
+                    "400": {
+                        "description": "Invalid output format",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/export/reservations": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Adds a new Food to the system with the provided details.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Streams reservations as a CSV or Excel file. Accepts the same filters and sort keys as listing reservations; pagination parameters are ignored.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "food"
+                    "export"
                 ],
-                "summary": "Create a New Food",
+                "summary": "Export reservations",
                 "parameters": [
                     {
-                        "description": "Food Details",
-                        "name": "food",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Food"
-                        }
+                        "type": "string",
+                        "description": "Start date (format: yyyy-mm-dd)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (format: yyyy-mm-dd)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "date",
+                            "status"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by food",
+                        "name": "food_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by side",
+                        "name": "side_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reserved",
+                            "served",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by payment state",
+                        "name": "is_paid",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created Food's details, including their unique identifier.",
+                    "200": {
+                        "description": "The exported reservations",
                         "schema": {
-                            "$ref": "#/definitions/models.Food"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for Food.",
+                        "description": "Invalid date, filter or output format",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Streams all users as a CSV or Excel file. Passwords are never exported.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export users",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The exported users",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "type": "file"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while creating the food.",
+                    "400": {
+                        "description": "Invalid output format",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/food/{id}": {
+        "/food": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves details of a single food dish by their unique identifier.",
+                "description": "Retrieves a page of foods in the system.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "food"
                 ],
-                "summary": "Get a Single food Dish",
+                "summary": "Get All Foods",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "food ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "rating_average",
+                            "rating_count"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by meal type",
+                        "name": "meal_type_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The details of the food including ID, name, quantity, category, mealtype.",
+                        "description": "A page of food objects.",
                         "schema": {
-                            "$ref": "#/definitions/models.Food"
+                            "$ref": "#/definitions/models.Page-models_Food"
                         }
                     },
                     "400": {
-                        "description": "Invalid food ID format.",
+                        "description": "Invalid pagination, sort or filter parameters.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "404": {
-                        "description": "Food not found with the specified ID.",
+                    "500": {
+                        "description": "Internal server error while fetching foods.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the details of an existing food identified by their ID.",
+                "description": "Adds a new Food to the system with the provided details.",
                 "consumes": [
                     "application/json"
                 ],
@@ -264,18 +566,10 @@ const docTemplate = `{
                 "tags": [
                     "food"
                 ],
-                "summary": "Update a food",
+                "summary": "Create a New Food",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Food ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated food Details",
+                        "description": "Food Details",
                         "name": "food",
                         "in": "body",
                         "required": true,
@@ -285,40 +579,42 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The updated food's details.",
+                    "201": {
+                        "description": "The created Food's details, including their unique identifier.",
                         "schema": {
                             "$ref": "#/definitions/models.Food"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details or invalid food ID.",
+                        "description": "Invalid input format for Food.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while updating the food.",
+                        "description": "Internal server error while creating the food.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/food/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Removes a food dish from the system by their unique identifier.",
+                "description": "Retrieves details of a single food dish by their unique identifier.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "food"
                 ],
-                "summary": "Delete a food",
+                "summary": "Get a Single food Dish",
                 "parameters": [
                     {
                         "type": "integer",
@@ -327,97 +623,51 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Food successfully deleted, no content to return."
-                    },
-                    "400": {
-                        "description": "Invalid food ID format.",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
                     },
-                    "500": {
-                        "description": "Internal server error while deleting the food.",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/me": {
-            "get": {
-                "security": [
                     {
-                        "Bearer": []
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
-                "description": "Retrieves the details of the currently authenticated user.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Get my profile",
                 "responses": {
                     "200": {
-                        "description": "The details of the currently authenticated user.",
+                        "description": "The details of the food including ID, name, quantity, category, mealtype.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Food"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "User not found.",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/me/qr": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Retrieves the QR Code of the currently authenticated user.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Get my profile QR CODE",
-                "responses": {
-                    "200": {
-                        "description": "The QR CODE of the currently authenticated user.",
+                    "304": {
+                        "description": "Not modified, If-None-Match names the current ETag."
+                    },
+                    "400": {
+                        "description": "Invalid food ID format.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "User not found.",
+                        "description": "Food not found with the specified ID.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            }
-        },
-        "/mealtype": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Adds a new MealType to the system with the provided details.",
+                "description": "Updates the details of an existing food identified by their ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -425,343 +675,530 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mealtype"
+                    "food"
                 ],
-                "summary": "Create a New MealType",
+                "summary": "Update a food",
                 "parameters": [
                     {
-                        "description": "MealType Details",
-                        "name": "mealtype",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated food Details",
+                        "name": "food",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MealType"
+                            "$ref": "#/definitions/models.Food"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created MealType's details, including their unique identifier.",
+                    "200": {
+                        "description": "The updated food's details.",
                         "schema": {
-                            "$ref": "#/definitions/models.MealType"
+                            "$ref": "#/definitions/models.Food"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for MealType.",
+                        "description": "Invalid input format for user details or invalid food ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Food not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while creating the mealtype.",
+                        "description": "Internal server error while updating the food.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            }
-        },
-        "/mealtype/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves details of a single mealtype dish by their unique identifier.",
+                "description": "Removes a food dish from the system by their unique identifier.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "mealtype"
+                    "food"
                 ],
-                "summary": "Get a Single mealtype Dish",
+                "summary": "Delete a food",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "mealtype ID",
+                        "description": "food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The details of the mealtype including ID, name, quantity, category, mealtype.",
-                        "schema": {
-                            "$ref": "#/definitions/models.MealType"
-                        }
+                    "204": {
+                        "description": "Food successfully deleted, no content to return."
                     },
                     "400": {
-                        "description": "Invalid mealtype ID format.",
+                        "description": "Invalid food ID format.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "MealType not found with the specified ID.",
+                        "description": "Food not found.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the food.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the details of an existing mealtype identified by their ID.",
+                "description": "Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name, quanity, description, CategoryID, MealTypeID.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "mealtype"
+                    "food"
                 ],
-                "summary": "Update a mealtype",
+                "summary": "Patch a food",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "MealType ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated mealtype Details",
-                        "name": "mealtype",
+                        "description": "Fields to change",
+                        "name": "patch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MealType"
+                            "type": "object"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated mealtype's details.",
+                        "description": "The patched food.",
                         "schema": {
-                            "$ref": "#/definitions/models.MealType"
+                            "$ref": "#/definitions/models.Food"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details or invalid mealtype ID.",
+                        "description": "Invalid food ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The patch changes fields only admins may change.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Food not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while updating the mealtype.",
+                        "description": "Internal server error while patching the food.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/food/{id}/image": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Removes a mealtype dish from the system by their unique identifier.",
+                "description": "Uploads a JPEG, PNG or GIF image for a food dish and generates a thumbnail. The content type is detected from the file content.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "mealtype"
+                    "food"
                 ],
-                "summary": "Delete a mealtype",
+                "summary": "Upload a food image",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "mealtype ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file (max 5MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "MealType successfully deleted, no content to return."
+                    "200": {
+                        "description": "The keys of the stored image and thumbnail.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImageResponse"
+                        }
                     },
                     "400": {
-                        "description": "Invalid mealtype ID format.",
+                        "description": "Invalid food ID or missing image file.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while deleting the mealtype.",
+                    "404": {
+                        "description": "Food not found with the specified ID.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
-                    }
-                }
-            }
-        },
-        "/mealtypes": {
+                    },
+                    "413": {
+                        "description": "Image is larger than 5MB or 25 megapixels.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "File is not a supported image.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while storing the image.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/food/{id}/reviews": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves a list of all mealtypes in the system.",
+                "description": "Retrieves the visible reviews of a food dish, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "mealtype"
+                    "review"
+                ],
+                "summary": "Get reviews of a food",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get All MealTypes",
                 "responses": {
                     "200": {
-                        "description": "An array of mealtype objects.",
+                        "description": "An array of reviews.",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.MealType"
+                                "$ref": "#/definitions/models.Review"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid food ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
                     "500": {
-                        "description": "Internal server error while fetching mealtypes.",
+                        "description": "Internal server error while fetching reviews.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/reservation": {
+        "/import/{resource}": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new reservation",
+                "description": "Bulk imports users (name, email, telephone, role, password), foods (name, quantity, category, meal_type), sides (name, quantity) or menus (meal_type, category, food, quantity) from a CSV file with a header line.\nRows are upserted by email for users and by name otherwise. The whole file is imported in one transaction and nothing is written if any row is invalid or dry_run is set.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "import"
                 ],
-                "summary": "Create a reservation",
+                "summary": "Import records from CSV",
                 "parameters": [
                     {
-                        "description": "Reservation details",
-                        "name": "reservation",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Reservation"
-                        }
+                        "enum": [
+                            "users",
+                            "foods",
+                            "sides",
+                            "menus"
+                        ],
+                        "type": "string",
+                        "description": "Resource to import",
+                        "name": "resource",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate the file without writing anything",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The created reservation's date",
+                        "description": "The import report.",
                         "schema": {
-                            "$ref": "#/definitions/v1.SuccessResponse"
+                            "$ref": "#/definitions/models.ImportReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Unknown resource, missing file or malformed CSV.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "403": {
-                        "description": "User must be logged in to update a reservation",
+                    "422": {
+                        "description": "The file contains invalid rows, nothing was imported.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/models.ImportReport"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error while importing.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/reservation/{id}": {
+        "/me": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve details of a single reservation by its unique identifier",
+                "description": "Retrieves the details of the currently authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "user"
                 ],
-                "summary": "Get a single reservation",
+                "summary": "Get my profile",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Reservation ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The reservation details",
+                        "description": "The details of the currently authenticated user.",
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.User"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid reservation ID format",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
+                    "304": {
+                        "description": "Not modified, If-None-Match names the current ETag."
                     },
-                    "403": {
-                        "description": "User must be logged in to update a reservation",
+                    "404": {
+                        "description": "User not found.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
-                    },
-                    "404": {
-                        "description": "Reservation not found",
+                    }
+                }
+            }
+        },
+        "/me/qr": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the QR Code of the currently authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my profile QR CODE",
+                "responses": {
+                    "200": {
+                        "description": "The QR CODE of the currently authenticated user.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "User not found.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/mealtype": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing reservation by ID",
+                "description": "Adds a new MealType to the system with the provided details.",
                 "consumes": [
                     "application/json"
                 ],
@@ -769,443 +1206,543 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "mealtype"
                 ],
-                "summary": "Update a reservation",
+                "summary": "Create a New MealType",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Reservation ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Reservation details",
-                        "name": "reservation",
+                        "description": "MealType Details",
+                        "name": "mealtype",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.MealType"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The updated reservation",
+                    "201": {
+                        "description": "The created MealType's details, including their unique identifier.",
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.MealType"
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "User must be logged in to update a reservation",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Reservation not found",
+                        "description": "Invalid input format for MealType.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error while creating the mealtype.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/mealtype/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a reservation by ID",
+                "description": "Retrieves details of a single mealtype dish by their unique identifier.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "mealtype"
                 ],
-                "summary": "Delete a reservation",
+                "summary": "Get a Single mealtype Dish",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Reservation ID",
+                        "format": "int64",
+                        "description": "mealtype ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content"
-                    },
-                    "400": {
-                        "description": "Invalid reservation ID format",
+                    "200": {
+                        "description": "The details of the mealtype including ID, name, quantity, category, mealtype.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/models.MealType"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "User must be logged in to update a reservation",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
+                    "304": {
+                        "description": "Not modified, If-None-Match names the current ETag."
                     },
-                    "404": {
-                        "description": "Reservation not found",
+                    "400": {
+                        "description": "Invalid mealtype ID format.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "MealType not found with the specified ID.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            }
-        },
-        "/reservations": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "List reservations based on provided start and end dates",
+                "description": "Updates the details of an existing mealtype identified by their ID.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "mealtype"
                 ],
-                "summary": "get reservations",
+                "summary": "Update a mealtype",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Start date (format: yyyy-mm-dd)",
-                        "name": "start_date",
-                        "in": "query"
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "MealType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated mealtype Details",
+                        "name": "mealtype",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MealType"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "End date (format: yyyy-mm-dd)",
-                        "name": "end_date",
-                        "in": "query"
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of reservations",
+                        "description": "The updated mealtype's details.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Reservation"
-                            }
+                            "$ref": "#/definitions/models.MealType"
                         }
                     },
                     "400": {
-                        "description": "Invalid date format",
+                        "description": "Invalid input format for user details or invalid mealtype ID.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "403": {
-                        "description": "User must be logged in to update a reservation",
+                    "404": {
+                        "description": "Meal type not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error while updating the mealtype.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sides": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Retrieves a list of all side dishes in the system.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sides"
-                ],
-                "summary": "Get All Sides",
-                "responses": {
-                    "200": {
-                        "description": "An array of sides objects.",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Sides"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error while fetching sides.",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Adds a new side dish to the system with the provided details.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Removes a mealtype dish from the system by their unique identifier.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sides"
+                    "mealtype"
                 ],
-                "summary": "Create a New Sides",
+                "summary": "Delete a mealtype",
                 "parameters": [
                     {
-                        "description": "Sides Details",
-                        "name": "sides",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Sides"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "mealtype ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created Side's details, including their unique identifier.",
+                    "204": {
+                        "description": "MealType successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid mealtype ID format.",
                         "schema": {
-                            "$ref": "#/definitions/models.Sides"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input format for Sides.",
+                    "404": {
+                        "description": "Meal type not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while creating the sides.",
+                        "description": "Internal server error while deleting the mealtype.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            }
-        },
-        "/sides/{id}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves details of a single side dish by their unique identifier.",
+                "description": "Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sides"
+                    "mealtype"
                 ],
-                "summary": "Get a Single Side Dish",
+                "summary": "Patch a meal type",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Sides ID",
+                        "description": "Meal type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The details of the sides including ID, name, quantity.",
+                        "description": "The patched meal type.",
                         "schema": {
-                            "$ref": "#/definitions/models.Sides"
+                            "$ref": "#/definitions/models.MealType"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid sides ID format.",
+                        "description": "Invalid meal type ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The patch changes fields only admins may change.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "Sides not found with the specified ID.",
+                        "description": "Meal type not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while patching the meal type.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/mealtypes": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the details of an existing side dish identified by their ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves a page of mealtypes in the system.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sides"
+                    "mealtype"
                 ],
-                "summary": "Update a Side Dish",
+                "summary": "Get All MealTypes",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "Side ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated Sides Details",
-                        "name": "sides",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Sides"
-                        }
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated side's details.",
+                        "description": "A page of mealtype objects.",
                         "schema": {
-                            "$ref": "#/definitions/models.Sides"
+                            "$ref": "#/definitions/models.Page-models_MealType"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details or invalid sides ID.",
+                        "description": "Invalid pagination, sort or filter parameters.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while updating the sides.",
+                        "description": "Internal server error while fetching mealtypes.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Removes a side dish from the system by their unique identifier.",
+            }
+        },
+        "/media/{key}": {
+            "get": {
+                "description": "Serves an uploaded image or thumbnail by its key. Keys are content addressed, so responses may be cached indefinitely.",
                 "produces": [
-                    "application/json"
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif"
                 ],
                 "tags": [
-                    "sides"
+                    "media"
                 ],
-                "summary": "Delete a Side Dish",
+                "summary": "Get a stored image",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Sides ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Image key, e.g. food/1/3f2a9c1b7d4e8f60_thumb.jpg",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Side Dish successfully deleted, no content to return."
-                    },
-                    "400": {
-                        "description": "Invalid sides ID format.",
+                    "200": {
+                        "description": "The image content.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "type": "file"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while deleting the sides.",
+                    "304": {
+                        "description": "The cached image is still valid."
+                    },
+                    "404": {
+                        "description": "Image not found.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/reports/production": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves a list of all users in the system.",
+                "description": "Counts the portions of each food and side per day and meal type, broken down by reservation status. Both dates are inclusive.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "user"
+                    "report"
+                ],
+                "summary": "Kitchen production report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (format: yyyy-mm-dd)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (format: yyyy-mm-dd)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get All Users",
                 "responses": {
                     "200": {
-                        "description": "An array of user objects.",
+                        "description": "Portions per day, meal type and dish",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.User"
+                                "$ref": "#/definitions/models.ProductionRow"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid date or output format",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
                     "500": {
-                        "description": "Internal server error while fetching users.",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/reservation": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Adds a new user to the system with the provided details.",
+                "description": "Create a new reservation",
                 "consumes": [
                     "application/json"
                 ],
@@ -1213,84 +1750,134 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "reservation"
                 ],
-                "summary": "Create a New User",
+                "summary": "Create a reservation",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
-                        "name": "user",
+                        "description": "Reservation details",
+                        "name": "reservation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Reservation"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key, a retry with the same key replays the first response instead of reserving again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created user's details, including their unique identifier.",
+                    "200": {
+                        "description": "The created reservation's date",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/v1.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details.",
+                        "description": "Invalid request format",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while creating the user.",
+                    "403": {
+                        "description": "User must be logged in to update a reservation",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
-                    }
-                }
-            }
-        },
-        "/users/{id}": {
+                    },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "422": {
+                        "description": "The Idempotency-Key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves details of a single user by their unique identifier.",
+                "description": "Retrieve details of a single reservation by its unique identifier",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "reservation"
                 ],
-                "summary": "Get a Single User",
+                "summary": "Get a single reservation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "User ID",
+                        "description": "Reservation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The details of the user including ID, name, email, telephone, and role.",
+                        "description": "The reservation details",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Reservation"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not modified, If-None-Match names the current ETag."
+                    },
                     "400": {
-                        "description": "Invalid user ID format.",
+                        "description": "Invalid reservation ID format",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The reservation belongs to another user",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "User not found with the specified ID.",
+                        "description": "Reservation not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
@@ -1301,7 +1888,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the details of an existing user identified by their ID.",
+                "description": "Update an existing reservation by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1309,45 +1896,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "reservation"
                 ],
-                "summary": "Update a User",
+                "summary": "Update a reservation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "User ID",
+                        "description": "Reservation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated User Details",
-                        "name": "user",
+                        "description": "Reservation details",
+                        "name": "reservation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Reservation"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated user's details.",
+                        "description": "The updated reservation",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Reservation"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details or invalid user ID.",
+                        "description": "Invalid request format",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The reservation belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while updating the user.",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
@@ -1358,245 +1975,2625 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Removes a user from the system by their unique identifier.",
+                "description": "Cancels a reservation by ID: its status becomes cancelled and it moves to the trash, where admins can restore it. Served reservations keep their status.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "reservation"
                 ],
-                "summary": "Delete a User",
+                "summary": "Cancel a reservation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "User ID",
+                        "description": "Reservation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "User successfully deleted, no content to return."
+                        "description": "No content"
                     },
                     "400": {
-                        "description": "Invalid user ID format.",
+                        "description": "Invalid reservation ID format",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The reservation belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while deleting the user.",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/users/{userId}/reservations": {
+        "/reservations": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves a list of reservations associated with a specific user.",
+                "description": "List the reservations of every user based on provided start and end dates. Only admins may list them, users list their own through /users/{userId}/reservations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "reservation"
                 ],
-                "summary": "Get User's Reservations",
+                "summary": "get reservations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (format: yyyy-mm-dd)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (format: yyyy-mm-dd)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "date",
+                            "status"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by food",
+                        "name": "food_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by side",
+                        "name": "side_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reserved",
+                            "served",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by payment state",
+                        "name": "is_paid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of reservations",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format or list parameters",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The caller is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: FoodID, SideID, date and, for admins, UserID and is_paid.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Patch a reservation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "An array of reservation objects for the user.",
+                        "description": "The patched reservation.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.Reservation"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format.",
+                        "description": "Invalid reservation ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The reservation belongs to another user, or the patch changes fields only admins may change.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "Reservations not found for the specified user ID.",
+                        "description": "Reservation not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while patching the reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Error message"
-                }
-            }
         },
-        "api.LoginDetails": {
-            "type": "object",
-            "properties": {
-                "email": {
+        "/reservations/{id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rates the food and side (1-5) of a served reservation and leaves an optional comment. Each reservation can be reviewed once by its owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Review a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ratings and comment",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created review.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format or rating out of range.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The reservation belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "409": {
+                        "description": "The reservation is not served yet or was already reviewed.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/serve": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Marks a reservation as served once the meal was handed out. Served reservations can be reviewed by their owner.",
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Mark a reservation as served",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Reservation marked as served"
+                    },
+                    "400": {
+                        "description": "Invalid reservation ID format",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves all reviews for moderation, including hidden ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get all reviews",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only return hidden (true) or visible (false) reviews",
+                        "name": "hidden",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of reviews.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Review"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hidden filter.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching reviews.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/hide": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Hides a review from the public review lists. The rating still counts towards the aggregates.",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Hide a review comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Review hidden."
+                    },
+                    "400": {
+                        "description": "Invalid review ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the review.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/unhide": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Makes a previously hidden review visible again.",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Show a hidden review comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Review visible."
+                    },
+                    "400": {
+                        "description": "Invalid review ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the review.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Full-text search over food and side names and descriptions, and over user names, emails and telephones. Falls back to fuzzy matching when nothing matches exactly.\nUsers can only be searched by admins. Snippets are HTML: the text is escaped and matches are highlighted with \u003cmark\u003e tags.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search foods, sides and users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "food",
+                            "side",
+                            "user"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated resource types to search, defaults to food,side (and user for admins)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of results (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matches ordered by rank.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing search text or invalid parameters.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Only admins can search users.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while searching.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/sides": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves a page of side dishes in the system.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Get All Sides",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "rating_average",
+                            "rating_count"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of sides objects.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_Sides"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching sides.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Adds a new side dish to the system with the provided details.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Create a New Sides",
+                "parameters": [
+                    {
+                        "description": "Sides Details",
+                        "name": "sides",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Sides"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created Side's details, including their unique identifier.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sides"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for Sides.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the sides.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/sides/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves details of a single side dish by their unique identifier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Get a Single Side Dish",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Sides ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the sides including ID, name, quantity.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sides"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified, If-None-Match names the current ETag."
+                    },
+                    "400": {
+                        "description": "Invalid sides ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Sides not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the details of an existing side dish identified by their ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Update a Side Dish",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Side ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Sides Details",
+                        "name": "sides",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Sides"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated side's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sides"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for user details or invalid sides ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Side dish not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the sides.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes a side dish from the system by their unique identifier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Delete a Side Dish",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Sides ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Side Dish successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid sides ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Side dish not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the sides.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name, quantity, description.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Patch a side dish",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Side dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The patched side dish.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sides"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid side dish ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The patch changes fields only admins may change.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Side dish not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while patching the side dish.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/sides/{id}/image": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Uploads a JPEG, PNG or GIF image for a side dish and generates a thumbnail. The content type is detected from the file content.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sides"
+                ],
+                "summary": "Upload a side dish image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Sides ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file (max 5MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The keys of the stored image and thumbnail.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sides ID or missing image file.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Sides not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "413": {
+                        "description": "Image is larger than 5MB or 25 megapixels.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "File is not a supported image.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while storing the image.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/sides/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the visible reviews of a side dish, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get reviews of a side dish",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Sides ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of reviews.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Review"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sides ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching reviews.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permanently deletes the rows of every resource that were deleted longer than the trash retention ago, in one transaction. Rows still referenced by other rows are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge all deleted rows",
+                "responses": {
+                    "200": {
+                        "description": "Number of purged rows per resource",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PurgeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{resource}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the soft-deleted rows of a resource with the time they were deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List deleted rows",
+                "parameters": [
+                    {
+                        "enum": [
+                            "reviews",
+                            "reservations",
+                            "foods",
+                            "sides",
+                            "categories",
+                            "mealtypes",
+                            "users"
+                        ],
+                        "type": "string",
+                        "description": "Resource",
+                        "name": "resource",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "deleted_at"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of deleted rows",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_TrashItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination or sort parameters",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown resource",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permanently deletes the rows of a resource that were deleted longer than the trash retention ago. Rows still referenced by other rows, such as a food with reservations, are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge deleted rows",
+                "parameters": [
+                    {
+                        "enum": [
+                            "reviews",
+                            "reservations",
+                            "foods",
+                            "sides",
+                            "categories",
+                            "mealtypes",
+                            "users"
+                        ],
+                        "type": "string",
+                        "description": "Resource",
+                        "name": "resource",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of purged rows",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PurgeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown resource",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{resource}/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Undeletes a soft-deleted row. Rows it references must not be deleted, for example a food is only restored once its category and meal type are.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted row",
+                "parameters": [
+                    {
+                        "enum": [
+                            "reviews",
+                            "reservations",
+                            "foods",
+                            "sides",
+                            "categories",
+                            "mealtypes",
+                            "users"
+                        ],
+                        "type": "string",
+                        "description": "Resource",
+                        "name": "resource",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Row ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Row restored, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown resource or no such deleted row",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "409": {
+                        "description": "A referenced row is deleted or was purged",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves a page of users in the system.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get All Users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "email",
+                            "role",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by telephone",
+                        "name": "telephone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by role",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of user objects.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching users.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Adds a new user to the system with the provided details.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a New User",
+                "parameters": [
+                    {
+                        "description": "User Registration Details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created user's details, including their unique identifier.",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for user details.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the user.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves details of a single user by their unique identifier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a Single User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the user including ID, name, email, telephone, and role.",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified, If-None-Match names the current ETag."
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the details of an existing user identified by their ID. Users may only update their own name, email and telephone, admins may update any user including the role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated User Details, role is only written for admins",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AdminUserDetails"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated user's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for user details or invalid user ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not the caller and the caller is not an admin.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the user.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes a user from the system by their unique identifier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the user.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name, email, telephone and, for admins, role.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Patch a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The patched user.",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the resource, send it as If-Match to update or delete it"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The patch changes fields only admins may change.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "The resource changed since the If-Match ETag was read.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is missing.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while patching the user.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves a list of reservations associated with a specific user. Users may only list their own reservations, admins those of any user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Get User's Reservations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from meta.next_cursor, only when sorting by id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "date",
+                            "status"
+                        ],
+                        "type": "string",
+                        "description": "Comma separated sort keys, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by food",
+                        "name": "food_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by side",
+                        "name": "side_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reserved",
+                            "served",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by payment state",
+                        "name": "is_paid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of reservation objects for the user.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format or list parameters.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "The user is not the caller and the caller is not an admin.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservations not found for the specified user ID.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.LoginDetails": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
                     "description": "Telephone string ` + "`" + `json:\"telephone\" example:\"09211212121\"` + "`" + `",
                     "type": "string",
                     "example": "user@example.com"
                 },
-                "password": {
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "api.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Login successful"
+                },
+                "token": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "api.RegisterDetails": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8,
+                    "example": "securePassword123"
+                },
+                "telephone": {
+                    "type": "string",
+                    "example": "123-456-7890"
+                }
+            }
+        },
+        "api.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "User registered successfully"
+                }
+            }
+        },
+        "models.AdminUserDetails": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "admin"
+                    ]
+                },
+                "telephone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditChange": {
+            "type": "object",
+            "properties": {
+                "from": {},
+                "to": {}
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "0af7651916cd43dd8448eb211c80319c"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "foods"
+                },
+                "resource_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.AuditVerification": {
+            "type": "object",
+            "properties": {
+                "broken_at": {
+                    "description": "BrokenAt is the first entry whose hash or link does not match, which\nwas altered itself or follows a removed entry.",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "head": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "foods": {
+                    "description": "Foods relationship",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Food"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be a valid email address"
+                }
+            }
+        },
+        "models.Food": {
+            "type": "object",
+            "required": [
+                "categoryID",
+                "mealTypeID",
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "description": "Category relationship",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    ]
+                },
+                "categoryID": {
+                    "description": "Foreign key for Category",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_key": {
+                    "type": "string"
+                },
+                "mealTypeID": {
+                    "description": "Foreign key for MealType",
+                    "type": "integer"
+                },
+                "meal_type": {
+                    "description": "MealType relationship",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MealType"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "quanity": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "description": "Aggregated from reviews and updated incrementally when a review is added",
+                    "type": "integer"
+                },
+                "thumbnail_key": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.ImportError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid email address"
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportError"
+                    }
+                },
+                "resource": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "users"
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 20
                 }
             }
         },
-        "api.LoginResponse": {
+        "models.MealType": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Login successful"
+                "foods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Food"
+                    }
                 },
-                "token": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string",
-                    "example": ""
+                    "maxLength": 100
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "api.RegisterDetails": {
+        "models.NewUser": {
             "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
             "properties": {
                 "email": {
-                    "type": "string",
-                    "example": "john.doe@example.com"
+                    "type": "string"
                 },
                 "name": {
                     "type": "string",
-                    "example": "John Doe"
+                    "maxLength": 100
                 },
                 "password": {
                     "type": "string",
-                    "example": "securePassword123"
+                    "maxLength": 72,
+                    "minLength": 8
                 },
                 "role": {
                     "type": "string",
-                    "example": "user"
+                    "enum": [
+                        "user",
+                        "admin"
+                    ]
                 },
                 "telephone": {
-                    "type": "string",
-                    "example": "123-456-7890"
+                    "type": "string"
                 }
             }
         },
-        "api.RegisterResponse": {
+        "models.Page-models_AuditEntry": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "User registered successfully"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
                 }
             }
         },
-        "models.Category": {
+        "models.Page-models_Food": {
             "type": "object",
             "properties": {
-                "foods": {
-                    "description": "Foods relationship",
+                "data": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Food"
                     }
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
                 }
             }
         },
-        "models.Food": {
+        "models.Page-models_MealType": {
             "type": "object",
             "properties": {
-                "category": {
-                    "description": "Category relationship",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Category"
-                        }
-                    ]
-                },
-                "categoryID": {
-                    "description": "Foreign key for Category",
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "mealTypeID": {
-                    "description": "Foreign key for MealType",
-                    "type": "integer"
-                },
-                "meal_type": {
-                    "description": "MealType relationship",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.MealType"
-                        }
-                    ]
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MealType"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
+                }
+            }
+        },
+        "models.Page-models_Reservation": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reservation"
+                    }
                 },
-                "quanity": {
-                    "type": "string"
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
+                }
+            }
+        },
+        "models.Page-models_Sides": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Sides"
+                    }
                 },
-                "reservations": {
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
+                }
+            }
+        },
+        "models.Page-models_TrashItem": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Reservation"
+                        "$ref": "#/definitions/models.TrashItem"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
                 }
             }
         },
-        "models.MealType": {
+        "models.Page-models_User": {
             "type": "object",
             "properties": {
-                "foods": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Food"
+                        "$ref": "#/definitions/models.User"
                     }
                 },
-                "id": {
-                    "type": "integer"
+                "meta": {
+                    "$ref": "#/definitions/models.PageMeta"
+                }
+            }
+        },
+        "models.PageMeta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 50
                 },
-                "name": {
-                    "type": "string"
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "models.ProductionRow": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                },
+                "item_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "item_name": {
+                    "type": "string",
+                    "example": "Kebab"
+                },
+                "item_type": {
+                    "type": "string",
+                    "example": "food"
+                },
+                "meal_type": {
+                    "type": "string",
+                    "example": "Lunch"
+                },
+                "portions": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "reserved": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "served": {
+                    "type": "integer",
+                    "example": 40
                 }
             }
         },
         "models.Reservation": {
             "type": "object",
+            "required": [
+                "date",
+                "foodID"
+            ],
             "properties": {
                 "date": {
                     "type": "string"
@@ -1625,9 +4622,13 @@ const docTemplate = `{
                     ]
                 },
                 "sideID": {
-                    "description": "Foreign key for Sides",
+                    "description": "Foreign key for Sides, nil without a side dish",
                     "type": "integer"
                 },
+                "status": {
+                    "type": "string",
+                    "example": "reserved"
+                },
                 "user": {
                     "description": "User relationship",
                     "allOf": [
@@ -1639,31 +4640,137 @@ const docTemplate = `{
                 "userID": {
                     "description": "Foreign key for User",
                     "type": "integer"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.Review": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Tasty and warm"
+                },
+                "food_id": {
+                    "type": "integer"
+                },
+                "food_rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "side_id": {
+                    "type": "integer"
+                },
+                "side_rating": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6
+                },
+                "snippet": {
+                    "description": "HTML escaped, with matches in \u003cmark\u003e tags",
+                    "type": "string",
+                    "example": "Grilled \u003cmark\u003ekebab\u003c/mark\u003e with saffron rice"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Kebab"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "food"
                 }
             }
         },
         "models.Sides": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "image_key": {
                     "type": "string"
                 },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
                 "quantity": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "description": "Aggregated from reviews and updated incrementally when a review is added",
+                    "type": "integer"
+                },
+                "thumbnail_key": {
                     "type": "string"
                 },
-                "reservations": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Reservation"
-                    }
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.TrashItem": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
         "models.User": {
             "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
             "properties": {
                 "email": {
                     "type": "string"
@@ -1672,10 +4779,8 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "name": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 },
                 "reservations": {
                     "type": "array",
@@ -1684,19 +4789,106 @@ const docTemplate = `{
                     }
                 },
                 "role": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "admin"
+                    ]
                 },
                 "telephone": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "problem.Details": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "food not found"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v1/food/42"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "v1.ImageResponse": {
+            "type": "object",
+            "properties": {
+                "image_key": {
+                    "type": "string",
+                    "example": "food/1/3f2a9c1b7d4e8f60.png"
+                },
+                "thumbnail_key": {
+                    "type": "string",
+                    "example": "food/1/3f2a9c1b7d4e8f60_thumb.jpg"
+                }
+            }
+        },
+        "v1.PurgeResponse": {
+            "type": "object",
+            "properties": {
+                "before": {
+                    "description": "Before is the time rows were deleted before to be purged",
+                    "type": "string"
+                },
+                "purged": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "v1.ErrorResponse": {
+        "v1.ReviewRequest": {
             "type": "object",
+            "required": [
+                "food_rating"
+            ],
             "properties": {
-                "error": {
+                "comment": {
                     "type": "string",
-                    "example": "Description of the error occurred"
+                    "maxLength": 1000,
+                    "example": "Tasty and warm"
+                },
+                "food_rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "side_rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 4
                 }
             }
         },
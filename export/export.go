@@ -0,0 +1,72 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer writes a table one row at a time. Close must be called to flush
+// the output and finish the file.
+type Writer interface {
+	WriteRow(row []string) error
+	Close() error
+}
+
+// ContentType returns the MIME type of files written in format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewWriter returns a Writer producing format on w.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// escape keeps spreadsheet applications from evaluating a cell as a formula:
+// values starting with =, +, -, @, a tab or a carriage return get a leading
+// single quote. Both writers escape every cell, so names and other user input
+// cannot run anything when an admin opens an export.
+func escape(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(row []string) error {
+	escaped := make([]string, len(row))
+	for i, cell := range row {
+		escaped[i] = escape(cell)
+	}
+	return c.w.Write(escaped)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
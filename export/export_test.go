@@ -0,0 +1,82 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"Kebab":             "Kebab",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+98 912 345 6789":  "'+98 912 345 6789",
+		"-2+3":              "'-2+3",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\t=1":              "'\t=1",
+		"\r=1":              "'\r=1",
+		"a=1":               "a=1",
+	}
+	for value, want := range tests {
+		if got := escape(value); got != want {
+			t.Errorf("escape(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestWritersEscape(t *testing.T) {
+	row := []string{"1", "=cmd|' /C calc'!A0"}
+
+	var csv bytes.Buffer
+	w := NewCSVWriter(&csv)
+	if err := w.WriteRow(row); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if got, want := csv.String(), "1,'=cmd|' /C calc'!A0\n"; got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+
+	var xlsx bytes.Buffer
+	w, err := NewXLSXWriter(&xlsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow(row); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if sheet := readSheet(t, xlsx.Bytes()); !strings.Contains(sheet, "<t xml:space=\"preserve\">&#39;=cmd|&#39; /C calc&#39;!A0</t>") {
+		t.Errorf("sheet does not hold the escaped cell: %s", sheet)
+	}
+}
+
+func readSheet(t *testing.T, data []byte) string {
+	t.Helper()
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range z.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		sheet, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(sheet)
+	}
+	t.Fatal("no worksheet in the file")
+	return ""
+}
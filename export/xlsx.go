@@ -0,0 +1,78 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// The static parts of a workbook with a single worksheet. Only the worksheet
+// itself depends on the data and is streamed last.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+}
+
+// NewXLSXWriter returns a Writer producing an Excel workbook. Rows are written
+// as inline strings straight into the zip stream, so memory use does not grow
+// with the number of rows.
+func NewXLSXWriter(w io.Writer) (Writer, error) {
+	z := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := z.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{zip: z, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) WriteRow(row []string) error {
+	var b strings.Builder
+	b.WriteString("<row>")
+	for _, cell := range row {
+		b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(&b, []byte(escape(cell)))
+		b.WriteString("</t></is></c>")
+	}
+	b.WriteString("</row>")
+
+	_, err := io.WriteString(x.sheet, b.String())
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
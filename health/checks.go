@@ -0,0 +1,35 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Hamedblue1381/restaurant-reserve/migrations"
+	"gorm.io/gorm"
+)
+
+// Database checks that a connection to db can be made.
+func Database(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Migrations checks that every migration has been applied to db, which can
+// change under a running server when an older version migrates down.
+func Migrations(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		pending, err := migrations.Pending(db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("migrations are not applied, starting with %04d_%s (%d pending)", pending[0].Version, pending[0].Name, len(pending))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+// Package health serves the liveness and readiness probes of the API.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckFunc reports whether a dependency can be used. It must return once ctx
+// is done.
+type CheckFunc func(ctx context.Context) error
+
+type entry struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker runs the readiness checks of the dependencies the API needs, such
+// as the database. Once Drain is called it reports the API as not ready, so
+// that load balancers stop sending requests before the server shuts down.
+type Checker struct {
+	timeout  time.Duration
+	checks   []entry
+	draining atomic.Bool
+}
+
+// New returns a checker that gives each check timeout to complete.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a readiness check. Checks are added while the application is
+// built, before requests are served.
+func (h *Checker) Add(name string, fn CheckFunc) {
+	h.checks = append(h.checks, entry{name, fn})
+}
+
+// Drain makes readiness fail from now on.
+func (h *Checker) Drain() {
+	h.draining.Store(true)
+}
+
+// Status is the outcome of a probe, or of a single check.
+type Status struct {
+	Status    string            `json:"status" example:"ok"`
+	Error     string            `json:"error,omitempty"`
+	LatencyMS float64           `json:"latency_ms,omitempty"`
+	Checks    map[string]Status `json:"checks,omitempty"`
+}
+
+const (
+	statusOK          = "ok"
+	statusFailing     = "failing"
+	statusUnavailable = "unavailable"
+	statusDraining    = "draining"
+)
+
+// Live answers 200 as long as the process can serve requests. It checks no
+// dependency, so that an outage of the database does not get the process
+// restarted.
+func (h *Checker) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, Status{Status: statusOK})
+}
+
+// Ready runs every check concurrently and answers 200 when all of them pass,
+// and 503 with the failing checks otherwise, or while draining.
+func (h *Checker) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, Status{Status: statusDraining})
+		return
+	}
+
+	results := make([]Status, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check entry) {
+			defer wg.Done()
+			results[i] = h.run(c.Request.Context(), check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status, code := Status{Status: statusOK, Checks: make(map[string]Status, len(h.checks))}, http.StatusOK
+	for i, check := range h.checks {
+		status.Checks[check.name] = results[i]
+		if results[i].Status != statusOK {
+			status.Status, code = statusUnavailable, http.StatusServiceUnavailable
+		}
+	}
+	c.JSON(code, status)
+}
+
+func (h *Checker) run(ctx context.Context, check entry) Status {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.fn(ctx)
+	result := Status{Status: statusOK, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "check", check.name, "error", err)
+		result.Status, result.Error = statusFailing, err.Error()
+	}
+	return result
+}
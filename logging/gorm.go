@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger logs the queries of GORM through slog: failed queries at error
+// level, queries slower than SlowThreshold at warn level and the others at
+// debug level. Missing rows are not errors. Query values are never logged,
+// as they include password hashes and personal data.
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+// NewGormLogger returns a GORM logger that warns about queries slower than
+// slow.
+func NewGormLogger(slow time.Duration) GormLogger {
+	return GormLogger{SlowThreshold: slow}
+}
+
+// LogMode is a no-op, the level is the one of the slog handler.
+func (l GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+// ParamsFilter drops the values of the query, so that only placeholders are
+// logged.
+func (GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+// unboundPlaceholder is how a numbered placeholder without a value comes out
+// of GORM, such as $1$.
+var unboundPlaceholder = regexp.MustCompile(`\$(\d+)\$`)
+
+func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	level, msg := slog.LevelDebug, "query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = slog.LevelError, "query failed"
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		level, msg = slog.LevelWarn, "slow query"
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", unboundPlaceholder.ReplaceAllString(sql, "$$$1")),
+		slog.Int64("rows", rows),
+		slog.Float64("elapsed_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if level == slog.LevelError {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,83 @@
+// Package logging sets up structured JSON logging. Records are tagged with
+// the request ID and trace ID of their context, and attributes that may hold
+// credentials are redacted.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redacted replaces the value of sensitive attributes.
+const Redacted = "[REDACTED]"
+
+// sensitive lists the attribute keys, compared in lower case, whose values
+// are never logged. Header names are keys too, so an Authorization header
+// logged in a group is redacted as well.
+var sensitive = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"password":            true,
+	"new_password":        true,
+	"token":               true,
+	"jwt_secret":          true,
+	"secret":              true,
+}
+
+// New returns a logger writing JSON lines to w, dropping records below level.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})})
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if sensitive[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of ctx, or "" outside of a request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID and the trace ID of the context to the
+// records logged with one, such as by slog.ErrorContext.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -2,69 +2,132 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
-	"github.com/joho/godotenv"
-
+	"github.com/Hamedblue1381/restaurant-reserve/app"
+	"github.com/Hamedblue1381/restaurant-reserve/cli"
 	"github.com/Hamedblue1381/restaurant-reserve/config"
+	"github.com/Hamedblue1381/restaurant-reserve/logging"
 	"github.com/Hamedblue1381/restaurant-reserve/routers"
-	"github.com/Hamedblue1381/restaurant-reserve/routers/api"
-	v1 "github.com/Hamedblue1381/restaurant-reserve/routers/api/v1"
+	"github.com/Hamedblue1381/restaurant-reserve/storage"
+	"github.com/Hamedblue1381/restaurant-reserve/tracing"
 )
 
 func main() {
-	// Load the env
-	err := godotenv.Load(".env")
+	// Errors in the configuration are logged at the default level, the
+	// configured one applies once it is loaded
+	slog.SetDefault(logging.New(os.Stderr, slog.LevelInfo))
 
+	// Load and validate the configuration before touching anything else
+	cfg, opts, err := config.Load(os.Args[1:])
+	if opts != nil && opts.PrintConfig {
+		if err := cfg.Print(); err != nil {
+			fatal("Failed to print the configuration", "error", err)
+		}
+	}
 	if err != nil {
-		log.Println("Error loading .env file")
+		fatal("Invalid configuration", "error", err)
+	}
+	if opts.PrintConfig {
+		return
 	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log.Level))
 
 	// Setup database connection
-	db := config.SetupDBConnection()
+	db := config.SetupDBConnection(cfg.Database)
 	if db == nil {
-		log.Fatal("Failed to connect to database!")
+		fatal("Failed to connect to database")
 	}
 
-	v1.InitializeReservationHandler(db)
-	v1.InitializedFoodHandler(db)
-	v1.InitializedMealTypeHandler(db)
-	v1.InitializedSidesHandler(db)
-	v1.InitializedUserHandler(db)
-	api.InitializedAuthHandler(db)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Run a command line subcommand instead of the server when one is given
+	if len(opts.Args) > 0 {
+		if !cli.IsCommand(opts.Args[0]) {
+			fatal("Unknown command", "command", opts.Args[0])
+		}
+		if err := cli.Run(ctx, db, opts.Args); err != nil {
+			fatal("Command failed", "error", err)
+		}
+		return
+	}
+
+	if err := config.CheckMigrations(db, cfg.Database); err != nil {
+		fatal("Database is not migrated", "error", err)
+	}
+
+	// Setup tracing before the database and router are instrumented
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		fatal("Failed to setup tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
+	// Setup blob storage for uploaded images
+	store, err := storage.NewLocalStore(cfg.Storage.Dir)
+	if err != nil {
+		fatal("Failed to setup blob storage", "error", err)
+	}
 
 	// Initialize router
-	r := routers.UseRouter()
+	a := app.New(cfg, db, store)
+	r := routers.UseRouter(a)
 
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	// Requests derive their context from base, so cancelling it aborts the
+	// queries still running when the shutdown grace period is over
+	base, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 
 	server := &http.Server{
-		Addr:    ":" + os.Getenv("PORT"),
-		Handler: r,
+		Addr:        ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return base },
 	}
 
 	go func() {
+		slog.Info("Listening", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen: %s\n", err)
+			fatal("Failed to listen", "error", err)
 		}
 	}()
 
 	<-ctx.Done()
 	stop()
 
+	// Fail readiness first and keep serving, so that load balancers take the
+	// instance out of rotation before it stops accepting connections
+	a.Health.Drain()
+	slog.Info("Draining before shutdown, press Ctrl+C again to force shutdown", "delay", time.Duration(cfg.Server.DrainDelay).String())
+	time.Sleep(time.Duration(cfg.Server.DrainDelay))
+
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 
-	fmt.Println("Gracefully shutting down server..., press Ctrl+C again to force shutdown")
+	slog.Info("Gracefully shutting down server..., press Ctrl+C again to force shutdown")
 	defer cancel()
 
 	if err := server.Shutdown(timeoutCtx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		cancelRequests()
+		server.Close()
+		fatal("Server forced to shutdown", "error", err)
 	}
 }
+
+// fatal logs an error and exits. Deferred calls do not run.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,89 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"net/http"
+
+	// Register the decoders for the formats accepted by Sniff
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// MaxPixels bounds the width x height of an image Thumbnail decodes. A small
+// file can declare huge dimensions, and decoding allocates 4 bytes per pixel.
+const MaxPixels = 25_000_000
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type, only JPEG, PNG and GIF are allowed")
+	ErrImageTooLarge    = errors.New("image must not be larger than 25 megapixels")
+)
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Sniff detects the content type from the leading bytes of data rather than
+// trusting the client supplied file name or header.
+func Sniff(data []byte) (contentType string, ext string, err error) {
+	contentType = http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedImage
+	}
+	return contentType, ext, nil
+}
+
+// Thumbnail decodes data and returns a JPEG scaled down to fit in a
+// size x size box, keeping the aspect ratio. Smaller images are not upscaled.
+// The dimensions are read from the header first, and images above MaxPixels
+// are rejected with ErrImageTooLarge before they are decoded.
+func Thumbnail(data []byte, size int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			height = height * size / width
+			width = size
+		} else {
+			width = width * size / height
+			height = size
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	// JPEG has no alpha channel, so flatten transparent images onto white
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
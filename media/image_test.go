@@ -0,0 +1,43 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200))); err != nil {
+		t.Fatal(err)
+	}
+
+	thumbnail, err := Thumbnail(buf.Bytes(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	if err != nil || format != "jpeg" || config.Width != 100 || config.Height != 50 {
+		t.Errorf("thumbnail is a %dx%d %s, %v, want a 100x50 jpeg", config.Width, config.Height, format, err)
+	}
+}
+
+func TestThumbnailRejectsHugeDimensions(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 1, 1), palette.Plan9), nil); err != nil {
+		t.Fatal(err)
+	}
+	// a few bytes long, but the logical screen claims 65535 x 65535 pixels
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint16(data[6:], 65535)
+	binary.LittleEndian.PutUint16(data[8:], 65535)
+
+	if _, err := Thumbnail(data, 100); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("Thumbnail() = %v, want ErrImageTooLarge", err)
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// Instrument times every query made through db and exports the stats of its
+// connection pool.
+func (m *Metrics) Instrument(db *gorm.DB) error {
+	if err := db.Use(gormPlugin{m}); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return m.Register(collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name()))
+}
+
+// gormPlugin observes the duration of each GORM operation.
+type gormPlugin struct {
+	m *Metrics
+}
+
+func (gormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", start),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", p.observe("create")),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", start),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", p.observe("query")),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", start),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", p.observe("update")),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", start),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", p.observe("delete")),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", start),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", p.observe("row")),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", start),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", p.observe("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func start(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func (p gormPlugin) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		p.m.DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(value.(time.Time)).Seconds())
+	}
+}
@@ -0,0 +1,121 @@
+// Package metrics collects the Prometheus metrics of the API: HTTP requests,
+// database queries and connection pool, the catalog cache, and business
+// events.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "restaurant"
+
+// Metrics holds the collectors of one application. Each has its own registry,
+// so several can exist side by side, for example in tests.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequests        *prometheus.CounterVec
+	HTTPRequestDuration *prometheus.HistogramVec
+	DBQueryDuration     *prometheus.HistogramVec
+
+	ReservationsCreated   *prometheus.CounterVec
+	ReservationsCancelled *prometheus.CounterVec
+	ReservationsServed    *prometheus.CounterVec
+	BlacklistRejections   prometheus.Counter
+	LoginFailures         *prometheus.CounterVec
+
+	CacheRequests      *prometheus.CounterVec
+	CacheInvalidations prometheus.Counter
+}
+
+// New creates the metrics, along with the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		DBQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Duration of database queries by operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"operation", "table"}),
+
+		ReservationsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_created_total",
+			Help:      "Reservations made, by meal type.",
+		}, []string{"meal_type"}),
+		ReservationsCancelled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_cancelled_total",
+			Help:      "Reservations deleted, by meal type.",
+		}, []string{"meal_type"}),
+		ReservationsServed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_served_total",
+			Help:      "Reservations served, by meal type.",
+		}, []string{"meal_type"}),
+		BlacklistRejections: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "blacklist_rejections_total",
+			Help:      "Reservations refused because the user is blacklisted.",
+		}),
+		LoginFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_failures_total",
+			Help:      "Failed sign ins, by reason.",
+		}, []string{"reason"}),
+
+		CacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Catalog reads by resource and result: hit, miss or error.",
+		}, []string{"resource", "result"}),
+		CacheInvalidations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_invalidations_total",
+			Help:      "Writes that invalidated the cached catalog.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPRequestDuration,
+		m.DBQueryDuration,
+		m.ReservationsCreated,
+		m.ReservationsCancelled,
+		m.ReservationsServed,
+		m.BlacklistRejections,
+		m.LoginFailures,
+		m.CacheRequests,
+		m.CacheInvalidations,
+	)
+	return m
+}
+
+// Register adds further collectors, such as the connection pool stats.
+func (m *Metrics) Register(collector prometheus.Collector) error {
+	return m.registry.Register(collector)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
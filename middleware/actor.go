@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/Hamedblue1381/restaurant-reserve/logging"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// Actor stores the client IP and request ID in the request context, as the
+// actor the audit log records changes for. The user is added once a token is
+// checked. It must run after RequestID.
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		actor := models.Actor{IP: c.ClientIP(), RequestID: logging.RequestID(ctx)}
+		c.Request = c.Request.WithContext(models.WithActor(ctx, actor))
+		c.Next()
+	}
+}
+
+// authenticated makes the user of claims the user of the request, for the
+// handlers, the trace and the audit log.
+func authenticated(c *gin.Context, claims *Claims) {
+	// Set user id to next handler for easy access
+	c.Set("id", claims.UserId)
+	c.Set("role", claims.Role)
+
+	ctx := c.Request.Context()
+	tracing.SetAttributes(ctx, tracing.UserID.Int64(int64(claims.UserId)))
+	actor := models.ActorFrom(ctx)
+	actor.UserID = claims.UserId
+	c.Request = c.Request.WithContext(models.WithActor(ctx, actor))
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxIdempotencyKey  = 255
+	maxIdempotentBody  = 10 << 20
+	idempotencyReplay  = "Idempotent-Replayed"
+	idempotencyKeyName = "Idempotency-Key"
+)
+
+// Idempotency replays the stored response when a POST, PUT, PATCH or DELETE
+// request is retried with the same Idempotency-Key header, so that a client
+// retrying on a flaky connection does not create the same reservation twice.
+// It must run after Auth, since keys belong to the authenticated user.
+//
+// A key reused with a different method, path or body is rejected with 422,
+// and a retry that arrives while the first request is still running gets 409.
+// Server errors are not stored, the request can be retried with the same key.
+func Idempotency(keys models.IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyName)
+		if key == "" || c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			problem.Respond(c, http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key must not be longer than 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBody+1))
+		if err != nil {
+			problem.Respond(c, http.StatusBadRequest, "invalid_body", "Unable to read request body")
+			return
+		}
+		if len(body) > maxIdempotentBody {
+			problem.Respond(c, http.StatusRequestEntityTooLarge, "payload_too_large", "Request body is too large to use an Idempotency-Key")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		io.WriteString(hash, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		userID := c.GetUint("id")
+		stored, err := keys.Begin(c.Request.Context(), userID, key, requestHash, ttl)
+		switch {
+		case errors.Is(err, models.ErrIdempotencyMismatch):
+			problem.Respond(c, http.StatusUnprocessableEntity, models.ErrIdempotencyMismatch.Code, err.Error())
+			return
+		case err != nil:
+			problem.Error(c, err, "Error checking idempotency key")
+			return
+		case stored != nil:
+			c.Header(idempotencyReplay, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// the request context may have ended, the outcome must be saved anyway
+		ctx := context.WithoutCancel(c.Request.Context())
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = keys.Release(ctx, userID, key)
+		} else {
+			err = keys.Complete(ctx, userID, key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "idempotency key not saved", "error", err)
+			c.Error(err)
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,45 +1,52 @@
 package middleware
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 )
 
-var jwtKey = []byte(os.Getenv("JWT_SECRET"))
-
 type Claims struct {
-	UserId uint `json:"id"`
-	Email string `json:"email"`
-	Role string `json:"role"`
+	UserId uint   `json:"id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
 	jwt.StandardClaims
 }
 
+// JWT issues and validates the tokens used to authenticate requests.
+type JWT struct {
+	key []byte
+	ttl time.Duration
+}
+
+func NewJWT(secret string, ttl time.Duration) *JWT {
+	return &JWT{key: []byte(secret), ttl: ttl}
+}
+
 // Generate Token for a given email ✨
-func GenerateToken(email string, userId uint, role string) (string, error) {
+func (j *JWT) GenerateToken(email string, userId uint, role string) (string, error) {
 
-	exprTime := time.Now().Add(24 * time.Hour)
+	exprTime := time.Now().Add(j.ttl)
 	claims := &Claims{
-		Email: email,
+		Email:  email,
 		UserId: userId,
-		Role: role,
+		Role:   role,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: exprTime.Unix(),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	tokenString, err := token.SignedString(j.key)
 
 	return tokenString, err
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+func (j *JWT) ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
+		return j.key, nil
 	})
 
 	if err != nil {
@@ -51,4 +58,4 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/logging"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID of a request, from the client or a proxy in
+// front of the API, and back in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern limits the IDs accepted from clients to what fits safely
+// in a log line.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID keeps the X-Request-ID of the request, or generates one when it
+// is missing or malformed, and echoes it in the response. The ID is stored in
+// the request context, so every record logged with that context carries it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Logger logs every request once it is done, with its route pattern, status,
+// latency, the signed in user and the code of the error it was answered with.
+// Server errors are logged at error level with their cause. Requests to the
+// quiet paths, such as probes, are logged at debug level when they succeed and
+// at warn level otherwise. At debug
+// level the request headers are included, with credentials redacted.
+func Logger(quiet ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		// the context still holds the request span, which ends before c.Next
+		// returns to the tracing middleware
+		ctx := c.Request.Context()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if id, ok := c.Get("id"); ok {
+			attrs = append(attrs, slog.Any("user_id", id))
+		}
+		if code := c.GetString(problem.CodeKey); code != "" {
+			attrs = append(attrs, slog.String("error_code", code))
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate); len(errs) > 0 {
+			attrs = append(attrs, slog.String("error", strings.Join(errs.Errors(), "; ")))
+		}
+
+		level := slog.LevelInfo
+		switch isQuiet := slices.Contains(quiet, c.Request.URL.Path); {
+		case isQuiet && status < 400:
+			level = slog.LevelDebug
+		case isQuiet:
+			// a failing probe is expected while draining
+			level = slog.LevelWarn
+		case status >= 500:
+			level = slog.LevelError
+		}
+		logger := slog.Default()
+		if logger.Enabled(ctx, slog.LevelDebug) {
+			headers := make([]any, 0, len(c.Request.Header))
+			for name, values := range c.Request.Header {
+				headers = append(headers, slog.Any(name, values))
+			}
+			attrs = append(attrs, slog.Group("headers", headers...))
+		}
+		logger.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts and times every request by method, route pattern and status
+// code. Requests that match no route share the "unmatched" route, so that
+// scanners cannot blow up the number of series.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
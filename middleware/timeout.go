@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout bounds the request context, and with it every query made for the
+// request, to the timeout configured for the route or to fallback. Routes are
+// keyed by method and path pattern, for example "GET /api/v1/export/users".
+// A timeout of zero leaves the request unbounded.
+//
+// When the context has ended by the time a handler writes an error, the error
+// is replaced with 504 Gateway Timeout if the deadline passed, or with 503
+// Service Unavailable if the request was cancelled by the client or by the
+// server shutting down.
+func Timeout(fallback time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = fallback
+		}
+
+		ctx := c.Request.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		w := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx, path: c.Request.URL.Path}
+		c.Writer = w
+		c.Next()
+		if w.replaced {
+			c.Set(problem.CodeKey, w.code)
+		}
+	}
+}
+
+// timeoutWriter rewrites error responses written after its context ended.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	path     string
+	replaced bool
+	code     string
+	body     []byte
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if code >= http.StatusBadRequest && !w.Written() {
+		var p *problem.Details
+		switch err := w.ctx.Err(); {
+		case errors.Is(err, context.DeadlineExceeded):
+			p = problem.New(http.StatusGatewayTimeout, "timeout", "Request timed out")
+		case errors.Is(err, context.Canceled):
+			p = problem.New(http.StatusServiceUnavailable, "cancelled", "Request was cancelled")
+		}
+		if p != nil {
+			p.Instance = w.path
+			code = p.Status
+			w.replaced = true
+			w.code = p.Code
+			w.body, _ = json.Marshal(p)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if !w.replaced {
+		return w.ResponseWriter.Write(data)
+	}
+	// the handler's own body describes the failed query, send ours once instead
+	if w.body != nil {
+		w.Header().Set("Content-Type", problem.ContentType)
+		if _, err := w.ResponseWriter.Write(w.body); err != nil {
+			return 0, err
+		}
+		w.body = nil
+	}
+	return len(data), nil
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -4,37 +4,34 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
 	"github.com/gin-gonic/gin"
 )
 
-func IsAuthorized() gin.HandlerFunc {
+func IsAuthorized(tokens *JWT) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing"})
-			c.Abort()
+			problem.Respond(c, http.StatusUnauthorized, "unauthorized", "Authorization header is missing")
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer <token>"})
-			c.Abort()
+			problem.Respond(c, http.StatusUnauthorized, "unauthorized", "Authorization header format must be Bearer <token>")
 			return
 		}
 
 		tokenString := parts[1]
 
-		claims, err := ValidateToken(tokenString)
+		claims, err := tokens.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized, Please login first!"})
-			c.Abort()
+			problem.Respond(c, http.StatusUnauthorized, "unauthorized", "Unauthorized, Please login first!")
 			return
 		}
 
-		// Set user id to next handler for easy access
-		c.Set("id", claims.UserId)
+		authenticated(c, claims)
 		c.Next()
 	}
 }
@@ -0,0 +1,211 @@
+// Package migrations applies the numbered SQL files embedded in the binary
+// and records them in the schema_migrations table.
+//
+// Files live in a directory per database dialect (postgres and sqlite) and
+// are named NNNN_description.up.sql and NNNN_description.down.sql. Both
+// dialects keep the same versions so their schemas stay in step. Every
+// migration runs in its own transaction.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single numbered schema change.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration records an applied migration.
+type SchemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// State describes a migration and whether it has been applied.
+type State struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load returns the migrations for dialect, ordered by version.
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s", dialect)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		content, err := files.ReadFile(path.Join(dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d has no up file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// applied returns the applied migrations keyed by version. It only reads, so
+// it is safe to call from the readiness probe: before the schema_migrations
+// table is created by Up, no migrations are applied.
+func applied(db *gorm.DB) (map[uint]SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[uint]SchemaMigration{}, nil
+	}
+
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]SchemaMigration, len(rows))
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// Status lists every known migration and when it was applied.
+func Status(db *gorm.DB) ([]State, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]State, len(migrations))
+	for i, m := range migrations {
+		states[i].Migration = m
+		if row, ok := done[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			states[i].AppliedAt = &appliedAt
+		}
+	}
+	return states, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func Pending(db *gorm.DB) ([]Migration, error) {
+	states, err := Status(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			pending = append(pending, state.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies all pending migrations in order and returns the ones it ran,
+// creating the schema_migrations table on first use.
+func Up(db *gorm.DB) ([]Migration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return pending[:i], fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Down reverts the last steps applied migrations and returns the ones it
+// reverted, most recent first.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	states, err := Status(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(states) - 1; i >= 0 && len(reverted) < steps; i-- {
+		m := states[i].Migration
+		if states[i].AppliedAt == nil {
+			continue
+		}
+		if m.Down == "" {
+			return reverted, fmt.Errorf("migration %04d_%s cannot be reverted", m.Version, m.Name)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		reverted = append(reverted, m)
+	}
+	return reverted, nil
+}
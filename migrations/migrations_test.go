@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func open(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:?_foreign_keys=1"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestPendingIsReadOnly(t *testing.T) {
+	db := open(t)
+	all, err := Load("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := Pending(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != len(all) {
+		t.Errorf("%d pending on an empty database, want %d", len(pending), len(all))
+	}
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		t.Error("Pending created the schema_migrations table")
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	db := open(t)
+	all, err := Load("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ran, err := Up(db); err != nil || len(ran) != len(all) {
+		t.Fatalf("Up() ran %d, %v, want %d", len(ran), err, len(all))
+	}
+	if pending, err := Pending(db); err != nil || len(pending) != 0 {
+		t.Fatalf("Pending() after Up = %d, %v", len(pending), err)
+	}
+
+	if reverted, err := Down(db, 1); err != nil || len(reverted) != 1 || reverted[0].Version != all[len(all)-1].Version {
+		t.Fatalf("Down(1) = %v, %v", reverted, err)
+	}
+	if pending, err := Pending(db); err != nil || len(pending) != 1 {
+		t.Errorf("Pending() after Down = %d, %v, want 1", len(pending), err)
+	}
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS reservations;
+DROP TABLE IF EXISTS foods;
+DROP TABLE IF EXISTS sides;
+DROP TABLE IF EXISTS meal_types;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
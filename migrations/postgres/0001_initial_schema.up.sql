@@ -0,0 +1,72 @@
+-- Schema as it was created by AutoMigrate, so existing databases can adopt
+-- migrations by simply running them.
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text,
+    email text,
+    telephone text,
+    role text,
+    password text
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE IF NOT EXISTS meal_types (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text
+);
+CREATE INDEX IF NOT EXISTS idx_meal_types_deleted_at ON meal_types (deleted_at);
+
+CREATE TABLE IF NOT EXISTS foods (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text,
+    quanity text,
+    category_id bigint,
+    meal_type_id bigint,
+    CONSTRAINT fk_categories_foods FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT fk_meal_types_foods FOREIGN KEY (meal_type_id) REFERENCES meal_types (id)
+);
+CREATE INDEX IF NOT EXISTS idx_foods_deleted_at ON foods (deleted_at);
+
+CREATE TABLE IF NOT EXISTS sides (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text,
+    quantity text
+);
+CREATE INDEX IF NOT EXISTS idx_sides_deleted_at ON sides (deleted_at);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    food_id bigint,
+    user_id bigint,
+    side_id bigint,
+    date timestamptz,
+    is_paid boolean,
+    CONSTRAINT fk_reservations_food FOREIGN KEY (food_id) REFERENCES foods (id),
+    CONSTRAINT fk_users_reservations FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_reservations_side FOREIGN KEY (side_id) REFERENCES sides (id)
+);
+CREATE INDEX IF NOT EXISTS idx_reservations_deleted_at ON reservations (deleted_at);
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS reviews;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS status;
+
+ALTER TABLE sides
+    DROP COLUMN IF EXISTS image_key,
+    DROP COLUMN IF EXISTS thumbnail_key,
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_average;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS image_key,
+    DROP COLUMN IF EXISTS thumbnail_key,
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_average;
@@ -0,0 +1,33 @@
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS image_key text,
+    ADD COLUMN IF NOT EXISTS thumbnail_key text,
+    ADD COLUMN IF NOT EXISTS rating_count bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_average double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE sides
+    ADD COLUMN IF NOT EXISTS image_key text,
+    ADD COLUMN IF NOT EXISTS thumbnail_key text,
+    ADD COLUMN IF NOT EXISTS rating_count bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_average double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS status text DEFAULT 'reserved';
+
+UPDATE reservations SET status = 'reserved' WHERE status IS NULL;
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    reservation_id bigint,
+    user_id bigint,
+    food_id bigint,
+    side_id bigint,
+    food_rating bigint,
+    side_rating bigint,
+    comment text,
+    hidden boolean
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_reservation_id ON reviews (reservation_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_deleted_at ON reviews (deleted_at);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_sides_name_trgm;
+DROP INDEX IF EXISTS idx_foods_name_trgm;
+DROP INDEX IF EXISTS idx_users_search;
+DROP INDEX IF EXISTS idx_sides_search;
+DROP INDEX IF EXISTS idx_foods_search;
+
+ALTER TABLE sides DROP COLUMN IF EXISTS description;
+ALTER TABLE foods DROP COLUMN IF EXISTS description;
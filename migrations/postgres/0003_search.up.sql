@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE foods ADD COLUMN IF NOT EXISTS description text;
+ALTER TABLE sides ADD COLUMN IF NOT EXISTS description text;
+
+-- The indexed expressions must match the documents built in models/search.go
+CREATE INDEX IF NOT EXISTS idx_foods_search ON foods
+    USING gin (to_tsvector('simple', name || ' ' || COALESCE(description, '')));
+CREATE INDEX IF NOT EXISTS idx_sides_search ON sides
+    USING gin (to_tsvector('simple', name || ' ' || COALESCE(description, '')));
+CREATE INDEX IF NOT EXISTS idx_users_search ON users
+    USING gin (to_tsvector('simple', name || ' ' || email || ' ' || telephone));
+
+CREATE INDEX IF NOT EXISTS idx_foods_name_trgm ON foods USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_sides_name_trgm ON sides USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin (name gin_trgm_ops);
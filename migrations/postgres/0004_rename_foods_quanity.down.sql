@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'foods' AND column_name = 'quantity'
+    ) THEN
+        ALTER TABLE foods RENAME COLUMN quantity TO quanity;
+    END IF;
+END $$;
@@ -0,0 +1,11 @@
+-- Databases created by AutoMigrate after the model was fixed already have
+-- the quantity column, so the rename only runs where it is missing.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'foods' AND column_name = 'quanity'
+    ) THEN
+        ALTER TABLE foods RENAME COLUMN quanity TO quantity;
+    END IF;
+END $$;
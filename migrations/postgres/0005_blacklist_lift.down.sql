@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS blacklist_lifted_at;
@@ -0,0 +1,2 @@
+-- Unpaid reservations made before a lift no longer count toward the blacklist
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blacklist_lifted_at timestamptz;
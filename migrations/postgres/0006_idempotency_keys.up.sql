@@ -0,0 +1,13 @@
+-- Responses of mutating requests, replayed when a client retries with the same Idempotency-Key
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id bigint NOT NULL,
+    key text NOT NULL,
+    request_hash text,
+    status_code bigint,
+    content_type text,
+    body bytea,
+    created_at timestamptz,
+    expires_at timestamptz,
+    PRIMARY KEY (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
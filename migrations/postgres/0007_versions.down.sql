@@ -0,0 +1,7 @@
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
+ALTER TABLE meal_types DROP COLUMN IF EXISTS version;
+ALTER TABLE foods DROP COLUMN IF EXISTS version;
+ALTER TABLE sides DROP COLUMN IF EXISTS version;
+ALTER TABLE reservations DROP COLUMN IF EXISTS version;
+ALTER TABLE reviews DROP COLUMN IF EXISTS version;
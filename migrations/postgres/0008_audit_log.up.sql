@@ -0,0 +1,32 @@
+-- Append-only record of every change made through the API or the CLI. Each
+-- entry hashes the one before it, see models/audit.go
+CREATE TABLE IF NOT EXISTS audit_log (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz NOT NULL,
+    actor_id bigint,
+    ip text,
+    request_id text,
+    action text NOT NULL,
+    resource text NOT NULL,
+    resource_id bigint NOT NULL,
+    old_values text,
+    new_values text,
+    prev_hash text NOT NULL UNIQUE,
+    hash text NOT NULL UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log (resource, resource_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log;
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,3 @@
+-- A request that never finished, because the server stopped, gives up its
+-- idempotency key once locked_until has passed
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until timestamptz;
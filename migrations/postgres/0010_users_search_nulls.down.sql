@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_search;
+CREATE INDEX IF NOT EXISTS idx_users_search ON users
+    USING gin (to_tsvector('simple', name || ' ' || email || ' ' || telephone));
@@ -0,0 +1,5 @@
+-- A user without a telephone made the whole document NULL, so the user could
+-- not be found by name or email. Must match the document in models/search.go.
+DROP INDEX IF EXISTS idx_users_search;
+CREATE INDEX IF NOT EXISTS idx_users_search ON users
+    USING gin (to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(email, '') || ' ' || COALESCE(telephone, '')));
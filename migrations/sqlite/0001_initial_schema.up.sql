@@ -0,0 +1,70 @@
+CREATE TABLE users (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name text,
+    email text,
+    telephone text,
+    role text,
+    password text
+);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE categories (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name text
+);
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE meal_types (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name text
+);
+CREATE INDEX idx_meal_types_deleted_at ON meal_types (deleted_at);
+
+CREATE TABLE foods (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name text,
+    quanity text,
+    category_id integer,
+    meal_type_id integer,
+    CONSTRAINT fk_categories_foods FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT fk_meal_types_foods FOREIGN KEY (meal_type_id) REFERENCES meal_types (id)
+);
+CREATE INDEX idx_foods_deleted_at ON foods (deleted_at);
+
+CREATE TABLE sides (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name text,
+    quantity text
+);
+CREATE INDEX idx_sides_deleted_at ON sides (deleted_at);
+
+CREATE TABLE reservations (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    food_id integer,
+    user_id integer,
+    side_id integer,
+    date datetime,
+    is_paid numeric,
+    CONSTRAINT fk_reservations_food FOREIGN KEY (food_id) REFERENCES foods (id),
+    CONSTRAINT fk_users_reservations FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_reservations_side FOREIGN KEY (side_id) REFERENCES sides (id)
+);
+CREATE INDEX idx_reservations_deleted_at ON reservations (deleted_at);
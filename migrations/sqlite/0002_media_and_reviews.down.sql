@@ -0,0 +1,13 @@
+DROP TABLE reviews;
+
+ALTER TABLE reservations DROP COLUMN status;
+
+ALTER TABLE sides DROP COLUMN image_key;
+ALTER TABLE sides DROP COLUMN thumbnail_key;
+ALTER TABLE sides DROP COLUMN rating_count;
+ALTER TABLE sides DROP COLUMN rating_average;
+
+ALTER TABLE foods DROP COLUMN image_key;
+ALTER TABLE foods DROP COLUMN thumbnail_key;
+ALTER TABLE foods DROP COLUMN rating_count;
+ALTER TABLE foods DROP COLUMN rating_average;
@@ -0,0 +1,28 @@
+ALTER TABLE foods ADD COLUMN image_key text;
+ALTER TABLE foods ADD COLUMN thumbnail_key text;
+ALTER TABLE foods ADD COLUMN rating_count integer NOT NULL DEFAULT 0;
+ALTER TABLE foods ADD COLUMN rating_average real NOT NULL DEFAULT 0;
+
+ALTER TABLE sides ADD COLUMN image_key text;
+ALTER TABLE sides ADD COLUMN thumbnail_key text;
+ALTER TABLE sides ADD COLUMN rating_count integer NOT NULL DEFAULT 0;
+ALTER TABLE sides ADD COLUMN rating_average real NOT NULL DEFAULT 0;
+
+ALTER TABLE reservations ADD COLUMN status text DEFAULT 'reserved';
+
+CREATE TABLE reviews (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    reservation_id integer,
+    user_id integer,
+    food_id integer,
+    side_id integer,
+    food_rating integer,
+    side_rating integer,
+    comment text,
+    hidden numeric
+);
+CREATE UNIQUE INDEX idx_reviews_reservation_id ON reviews (reservation_id);
+CREATE INDEX idx_reviews_deleted_at ON reviews (deleted_at);
@@ -0,0 +1,2 @@
+ALTER TABLE sides DROP COLUMN description;
+ALTER TABLE foods DROP COLUMN description;
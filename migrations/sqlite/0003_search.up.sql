@@ -0,0 +1,3 @@
+-- SQLite has no pg_trgm or tsvector, search falls back to LIKE
+ALTER TABLE foods ADD COLUMN description text;
+ALTER TABLE sides ADD COLUMN description text;
@@ -0,0 +1 @@
+ALTER TABLE foods RENAME COLUMN quanity TO quantity;
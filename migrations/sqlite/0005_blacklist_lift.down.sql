@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN blacklist_lifted_at;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN blacklist_lifted_at datetime;
@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    user_id integer NOT NULL,
+    key text NOT NULL,
+    request_hash text,
+    status_code integer,
+    content_type text,
+    body blob,
+    created_at datetime,
+    expires_at datetime,
+    PRIMARY KEY (user_id, key)
+);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,7 @@
+ALTER TABLE users DROP COLUMN version;
+ALTER TABLE categories DROP COLUMN version;
+ALTER TABLE meal_types DROP COLUMN version;
+ALTER TABLE foods DROP COLUMN version;
+ALTER TABLE sides DROP COLUMN version;
+ALTER TABLE reservations DROP COLUMN version;
+ALTER TABLE reviews DROP COLUMN version;
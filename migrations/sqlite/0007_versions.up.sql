@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE meal_types ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE foods ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE sides ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE reservations ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE reviews ADD COLUMN version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,28 @@
+-- Append-only record of every change made through the API or the CLI. Each
+-- entry hashes the one before it, see models/audit.go
+CREATE TABLE audit_log (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NOT NULL,
+    actor_id integer,
+    ip text,
+    request_id text,
+    action text NOT NULL,
+    resource text NOT NULL,
+    resource_id integer NOT NULL,
+    old_values text,
+    new_values text,
+    prev_hash text NOT NULL UNIQUE,
+    hash text NOT NULL UNIQUE
+);
+CREATE INDEX idx_audit_log_resource ON audit_log (resource, resource_id);
+CREATE INDEX idx_audit_log_actor_id ON audit_log (actor_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
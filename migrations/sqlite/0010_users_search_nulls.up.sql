@@ -0,0 +1 @@
+-- SQLite has no search index, the LIKE search is not affected by NULLs
@@ -0,0 +1,376 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit actions. Creates, updates and deletes are recorded for every model;
+// the others name changes that are looked for on their own.
+const (
+	AuditCreate         = "create"
+	AuditUpdate         = "update"
+	AuditDelete         = "delete"
+	AuditPayment        = "payment"
+	AuditBlacklistLift  = "blacklist_lift"
+	AuditPasswordReset  = "password_reset"
+	AuditRestore        = "restore"
+	AuditPurge          = "purge"
+	auditPostgresLockID = 0x61756469746c6f67 // "auditlog"
+)
+
+// errAuditBroken stops the walk of the chain at the first broken entry.
+var errAuditBroken = errors.New("audit log chain is broken")
+
+// auditRedacted lists the columns whose values are replaced by a fingerprint
+// in audit entries, so that a change is visible without the value.
+var auditRedacted = map[string]bool{
+	"password": true,
+}
+
+// auditSkipped lists the columns left out of audit entries, as the time of a
+// change is the time of its entry.
+var auditSkipped = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+var AuditListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":         "audit_log.id",
+		"created_at": "audit_log.created_at",
+	},
+	Filters: map[string]Field{
+		"actor_id":    {"audit_log.actor_id", FieldUint},
+		"action":      {"audit_log.action", FieldString},
+		"resource":    {"audit_log.resource", FieldString},
+		"resource_id": {"audit_log.resource_id", FieldUint},
+		"request_id":  {"audit_log.request_id", FieldString},
+		"ip":          {"audit_log.ip", FieldString},
+	},
+}
+
+// AuditEntry records a single change: who made it, from where, and the row
+// before and after. Entries are never updated or deleted, which triggers in
+// the database enforce, and each one hashes the previous entry so that any
+// tampering breaks the chain, see VerifyAudit. Before and After hold the
+// columns of the row, null where it did not exist, and Changes the columns
+// that differ between them.
+type AuditEntry struct {
+	ID         uint      `gorm:"primaryKey" json:"id" example:"1"`
+	CreatedAt  time.Time `gorm:"not null;index" json:"created_at"`
+	ActorID    *uint     `gorm:"index" json:"actor_id" example:"1"`
+	IP         string    `json:"ip" example:"203.0.113.7"`
+	RequestID  string    `json:"request_id" example:"0af7651916cd43dd8448eb211c80319c"`
+	Action     string    `gorm:"not null" json:"action" example:"update"`
+	Resource   string    `gorm:"not null;index:idx_audit_log_resource" json:"resource" example:"foods"`
+	ResourceID uint      `gorm:"not null;index:idx_audit_log_resource" json:"resource_id" example:"3"`
+	OldValues  string    `json:"-"`
+	NewValues  string    `json:"-"`
+	PrevHash   string    `gorm:"not null;uniqueIndex" json:"prev_hash"`
+	Hash       string    `gorm:"not null;uniqueIndex" json:"hash"`
+
+	Before  json.RawMessage        `gorm:"-" json:"before" swaggertype:"object"`
+	After   json.RawMessage        `gorm:"-" json:"after" swaggertype:"object"`
+	Changes map[string]AuditChange `gorm:"-" json:"changes"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+// AuditChange is the old and new value of a column.
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+func (e *AuditEntry) AfterFind(tx *gorm.DB) error {
+	e.Before, e.After = rawSnapshot(e.OldValues), rawSnapshot(e.NewValues)
+	before, after := map[string]interface{}{}, map[string]interface{}{}
+	if e.OldValues != "" {
+		if err := json.Unmarshal([]byte(e.OldValues), &before); err != nil {
+			return err
+		}
+	}
+	if e.NewValues != "" {
+		if err := json.Unmarshal([]byte(e.NewValues), &after); err != nil {
+			return err
+		}
+	}
+
+	e.Changes = map[string]AuditChange{}
+	// a column missing on one side is null there
+	for column, value := range after {
+		if old := before[column]; !reflect.DeepEqual(old, value) {
+			e.Changes[column] = AuditChange{From: old, To: value}
+		}
+	}
+	for column, old := range before {
+		if _, ok := after[column]; !ok && old != nil {
+			e.Changes[column] = AuditChange{From: old}
+		}
+	}
+	return nil
+}
+
+func rawSnapshot(values string) json.RawMessage {
+	if values == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(values)
+}
+
+// computeHash hashes the fields of e with the hash of the previous entry.
+func (e *AuditEntry) computeHash() string {
+	payload, _ := json.Marshal(struct {
+		PrevHash   string `json:"prev_hash"`
+		CreatedAt  string `json:"created_at"`
+		ActorID    *uint  `json:"actor_id"`
+		IP         string `json:"ip"`
+		RequestID  string `json:"request_id"`
+		Action     string `json:"action"`
+		Resource   string `json:"resource"`
+		ResourceID uint   `json:"resource_id"`
+		OldValues  string `json:"old_values"`
+		NewValues  string `json:"new_values"`
+	}{
+		e.PrevHash, e.CreatedAt.UTC().Format(time.RFC3339Nano), e.ActorID, e.IP, e.RequestID,
+		e.Action, e.Resource, e.ResourceID, e.OldValues, e.NewValues,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Actor is who makes a change and from where. Requests carry one in their
+// context; changes made from the command line have none.
+type Actor struct {
+	UserID    uint
+	IP        string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns ctx carrying actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor of ctx, or the zero Actor outside of a request.
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// audited runs write in a transaction and records the row id of T before and
+// after it in the audit log. id is read once write returned, so that it can
+// point at the ID a create assigns. It returns the row after the write, or nil
+// when the row is gone.
+func audited[T any](db *gorm.DB, action string, id *uint, write func(tx *gorm.DB) error) (*T, error) {
+	var after *T
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var before *T
+		if *id != 0 {
+			var err error
+			if before, err = findAudited[T](tx, *id); err != nil {
+				return err
+			}
+		}
+		if err := write(tx); err != nil {
+			return err
+		}
+		var err error
+		if after, err = findAudited[T](tx, *id); err != nil {
+			return err
+		}
+		return appendAudit(tx, action, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// findAudited returns the row id of T, soft-deleted or not, or nil when there
+// is none.
+func findAudited[T any](tx *gorm.DB, id uint) (*T, error) {
+	row := new(T)
+	result := tx.Unscoped().Limit(1).Find(row, id)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return row, nil
+}
+
+// appendAudit adds an entry for the change of a row from before to after,
+// either of which may be nil, to the end of the chain. A change that left the
+// row as it was is not recorded.
+func appendAudit(tx *gorm.DB, action string, before, after interface{}) error {
+	model := after
+	if model == nil || reflect.ValueOf(model).IsNil() {
+		model = before
+	}
+	if model == nil || reflect.ValueOf(model).IsNil() {
+		return nil
+	}
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(tx.Statement.Context, reflect.Indirect(reflect.ValueOf(model)))
+
+	oldValues, err := snapshot(tx, stmt, before)
+	if err != nil {
+		return err
+	}
+	newValues, err := snapshot(tx, stmt, after)
+	if err != nil {
+		return err
+	}
+	if oldValues == newValues {
+		return nil
+	}
+	return appendAuditEntry(tx, &AuditEntry{
+		Action:     action,
+		Resource:   stmt.Schema.Table,
+		ResourceID: id.(uint),
+		OldValues:  oldValues,
+		NewValues:  newValues,
+	})
+}
+
+// appendAuditEntry completes entry with the actor of the context and links it
+// to the last entry of the chain.
+func appendAuditEntry(tx *gorm.DB, entry *AuditEntry) error {
+	actor := ActorFrom(tx.Statement.Context)
+	if actor.UserID != 0 {
+		entry.ActorID = &actor.UserID
+	}
+	entry.IP, entry.RequestID = actor.IP, actor.RequestID
+
+	// Concurrent writers must not both extend the same entry. SQLite already
+	// allows a single writer at a time.
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditPostgresLockID).Error; err != nil {
+			return err
+		}
+	}
+	var last []string
+	if err := tx.Model(&AuditEntry{}).Order("id DESC").Limit(1).Pluck("hash", &last).Error; err != nil {
+		return err
+	}
+	if len(last) > 0 {
+		entry.PrevHash = last[0]
+	}
+
+	// Databases keep microseconds at most, the hash must match what is read back
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.Hash = entry.computeHash()
+	return tx.Create(entry).Error
+}
+
+// snapshot encodes the columns of row as a JSON object, with its keys sorted,
+// or returns "" for a nil row.
+func snapshot(tx *gorm.DB, stmt *gorm.Statement, row interface{}) (string, error) {
+	if row == nil {
+		return "", nil
+	}
+	value := reflect.ValueOf(row)
+	if value.Kind() == reflect.Pointer && value.IsNil() {
+		return "", nil
+	}
+
+	values := map[string]interface{}{}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || auditSkipped[field.DBName] {
+			continue
+		}
+		v, zero := field.ValueOf(tx.Statement.Context, reflect.Indirect(value))
+		if auditRedacted[field.DBName] && !zero {
+			sum := sha256.Sum256([]byte(v.(string)))
+			v = "redacted:" + hex.EncodeToString(sum[:4])
+		}
+		values[field.DBName] = v
+	}
+	encoded, err := json.Marshal(values)
+	return string(encoded), err
+}
+
+// auditAction names an update by what it changes.
+func auditAction(changes map[string]interface{}) string {
+	if _, ok := changes["is_paid"]; ok {
+		return AuditPayment
+	}
+	return AuditUpdate
+}
+
+type AuditHandler struct {
+	db *gorm.DB
+}
+
+func NewAuditHandler(db *gorm.DB) *AuditHandler {
+	return &AuditHandler{db}
+}
+
+// ListAudit lists the audit entries made between since and until, either of
+// which may be zero.
+func (h *AuditHandler) ListAudit(ctx context.Context, since, until time.Time, q *ListQuery) (*Page[AuditEntry], error) {
+	query := h.db.WithContext(ctx).Model(&AuditEntry{})
+	if !since.IsZero() {
+		query = query.Where("audit_log.created_at >= ?", since)
+	}
+	if !until.IsZero() {
+		query = query.Where("audit_log.created_at < ?", until)
+	}
+	return paginate(query, q, "audit_log.id", func(entry *AuditEntry) uint { return entry.ID })
+}
+
+// AuditVerification is the outcome of checking the audit log chain.
+type AuditVerification struct {
+	Valid   bool   `json:"valid" example:"true"`
+	Entries int64  `json:"entries" example:"120"`
+	Head    string `json:"head,omitempty"`
+	// BrokenAt is the first entry whose hash or link does not match, which
+	// was altered itself or follows a removed entry.
+	BrokenAt *uint  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerifyAudit recomputes the hash of every entry in order and checks that
+// each links to the one before.
+func (h *AuditHandler) VerifyAudit(ctx context.Context) (*AuditVerification, error) {
+	verification := &AuditVerification{Valid: true}
+	var entries []AuditEntry
+	result := h.db.WithContext(ctx).FindInBatches(&entries, 500, func(tx *gorm.DB, batch int) error {
+		for i := range entries {
+			entry := &entries[i]
+			reason := ""
+			switch {
+			case entry.PrevHash != verification.Head:
+				reason = "does not link to the previous entry"
+			case entry.Hash != entry.computeHash():
+				reason = "hash does not match its content"
+			}
+			if reason != "" {
+				id := entry.ID
+				verification.Valid, verification.BrokenAt, verification.Reason = false, &id, reason
+				return errAuditBroken
+			}
+			verification.Head = entry.Hash
+			verification.Entries++
+		}
+		return nil
+	})
+	if result.Error != nil && !errors.Is(result.Error, errAuditBroken) {
+		return nil, result.Error
+	}
+	return verification, nil
+}
@@ -0,0 +1,86 @@
+package models_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"gorm.io/gorm"
+)
+
+// auditedDB returns a migrated in-memory database with an audit log of three
+// entries, one for each user created.
+func auditedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := memoryDB(t)
+
+	users := models.NewUserHandler(db)
+	for i := 1; i <= 3; i++ {
+		user := models.User{Name: fmt.Sprint("User ", i), Email: fmt.Sprintf("user%d@example.com", i), Password: "password123"}
+		if err := users.CreateUser(context.Background(), &user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func verify(t *testing.T, db *gorm.DB) *models.AuditVerification {
+	t.Helper()
+	verification, err := models.NewAuditHandler(db).VerifyAudit(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verification
+}
+
+func TestAuditAppendOnly(t *testing.T) {
+	db := auditedDB(t)
+	if v := verify(t, db); !v.Valid || v.Entries != 3 {
+		t.Fatalf("VerifyAudit() = %+v, want 3 valid entries", v)
+	}
+
+	if err := db.Exec("UPDATE audit_log SET new_values = '{}' WHERE id = 2").Error; err == nil {
+		t.Error("audit entry was updated")
+	}
+	if err := db.Exec("DELETE FROM audit_log WHERE id = 2").Error; err == nil {
+		t.Error("audit entry was deleted")
+	}
+	if v := verify(t, db); !v.Valid || v.Entries != 3 {
+		t.Errorf("VerifyAudit() = %+v after blocked writes, want 3 valid entries", v)
+	}
+}
+
+// The tests below drop the triggers first, as someone with access to the
+// schema could, to check that the chain still reveals the change.
+
+func TestAuditEditedPayload(t *testing.T) {
+	db := auditedDB(t)
+	if err := db.Exec("DROP TRIGGER audit_log_no_update").Error; err != nil {
+		t.Fatal(err)
+	}
+	err := db.Exec(`UPDATE audit_log SET new_values = replace(new_values, 'User 2', 'Someone else') WHERE id = 2`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := verify(t, db)
+	if v.Valid || v.BrokenAt == nil || *v.BrokenAt != 2 || v.Entries != 1 {
+		t.Errorf("VerifyAudit() = %+v, want broken at 2 after 1 valid entry", v)
+	}
+}
+
+func TestAuditRemovedEntry(t *testing.T) {
+	db := auditedDB(t)
+	if err := db.Exec("DROP TRIGGER audit_log_no_delete").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("DELETE FROM audit_log WHERE id = 2").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	v := verify(t, db)
+	if v.Valid || v.BrokenAt == nil || *v.BrokenAt != 3 {
+		t.Errorf("VerifyAudit() = %+v, want broken at 3, which follows the removed entry", v)
+	}
+}
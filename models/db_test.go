@@ -0,0 +1,22 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/Hamedblue1381/restaurant-reserve/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// memoryDB returns a migrated in-memory database that is closed when the
+// test ends.
+func memoryDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := config.SetupDBConnection(config.DatabaseConfig{Driver: config.DriverSQLiteMemory})
+	db.Logger = logger.Discard
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db
+}
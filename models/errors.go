@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Kind classifies the errors of the models, so that the API can map them to
+// status codes in one place.
+type Kind uint8
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindForbidden
+	KindValidation
+	KindBlacklisted
+	KindPrecondition
+)
+
+// FieldError describes why the value of a single field was rejected.
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
+
+// Error is a domain error with a stable, machine readable code. Errors with
+// the same code match each other in errors.Is, so a sentinel can be wrapped
+// or copied with more detail.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+var (
+	ErrNotFound         = &Error{Kind: KindNotFound, Code: "not_found", Message: "resource not found"}
+	ErrConflict         = &Error{Kind: KindConflict, Code: "conflict", Message: "resource already exists"}
+	ErrInvalidReference = &Error{Kind: KindValidation, Code: "invalid_reference", Message: "referenced resource does not exist"}
+	ErrValidation       = &Error{Kind: KindValidation, Code: "validation_failed", Message: "request is invalid"}
+	ErrBlacklisted      = &Error{Kind: KindBlacklisted, Code: "blacklisted", Message: "user is blacklisted due to having more than 3 unpaid reservations"}
+)
+
+// ValidationError returns ErrValidation with the given field errors.
+func ValidationError(fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: ErrValidation.Code, Message: ErrValidation.Message, Fields: fields}
+}
+
+// DomainError returns err as a domain error where the database error has a
+// domain meaning: missing rows, and unique or foreign key violations, which
+// are reported when the connection translates errors. Other errors are
+// returned unchanged.
+func DomainError(err error) error {
+	var domain *Error
+	switch {
+	case errors.As(err, &domain):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return notFound(err, "resource")
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return &Error{Kind: KindConflict, Code: ErrConflict.Code, Message: ErrConflict.Message, Err: err}
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return &Error{Kind: KindValidation, Code: ErrInvalidReference.Code, Message: ErrInvalidReference.Message, Err: err}
+	}
+	return err
+}
+
+// notFound replaces gorm.ErrRecordNotFound with the not found error of
+// resource and returns other errors unchanged. The result still matches
+// gorm.ErrRecordNotFound.
+func notFound(err error, resource string) error {
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return &Error{Kind: KindNotFound, Code: ErrNotFound.Code, Message: resource + " not found", Err: err}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReservationExport is a flattened reservation row used for exports.
+type ReservationExport struct {
+	ID        uint
+	Date      time.Time
+	Status    string
+	IsPaid    bool
+	UserID    uint
+	UserName  string
+	UserEmail string
+	FoodID    uint
+	FoodName  string
+	SideID    uint
+	SideName  string
+}
+
+// FoodExport is a flattened food row used for exports.
+type FoodExport struct {
+	ID            uint
+	Name          string
+	Quanity       string `gorm:"column:quantity"`
+	CategoryName  string
+	MealTypeName  string
+	RatingCount   int
+	RatingAverage float64
+}
+
+// eachRow runs query and calls fn for every row as it is read from the
+// database, so the whole result is never held in memory.
+func eachRow[T any](query *gorm.DB, fn func(*T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := query.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportReservations streams the reservations matching the same filters and
+// sort order as ListReservations to fn. Pagination is ignored.
+func (r *ReservationHandler) ExportReservations(ctx context.Context, startDate, endDate time.Time, q *ListQuery, fn func(*ReservationExport) error) error {
+	query := filterReservationDates(r.db.WithContext(ctx).Model(&Reservation{}), startDate, endDate)
+	query = q.order(q.filter(query), "reservations.id").
+		Select(`reservations.id, reservations.date, reservations.status, reservations.is_paid,
+			reservations.user_id, COALESCE(users.name, '') AS user_name, COALESCE(users.email, '') AS user_email,
+			reservations.food_id, COALESCE(foods.name, '') AS food_name,
+			reservations.side_id, COALESCE(sides.name, '') AS side_name`).
+		Joins("LEFT JOIN users ON users.id = reservations.user_id").
+		Joins("LEFT JOIN foods ON foods.id = reservations.food_id").
+		Joins("LEFT JOIN sides ON sides.id = reservations.side_id")
+
+	return eachRow(query, fn)
+}
+
+// ExportUsers streams all users to fn.
+func (h *UserHandler) ExportUsers(ctx context.Context, fn func(*User) error) error {
+	return eachRow(h.db.WithContext(ctx).Model(&User{}).Order("id"), fn)
+}
+
+// ExportFoods streams all foods with their category and meal type names to fn.
+func (f *FoodHandler) ExportFoods(ctx context.Context, fn func(*FoodExport) error) error {
+	query := f.db.WithContext(ctx).Model(&Food{}).
+		Select(`foods.id, foods.name, foods.quantity,
+			COALESCE(categories.name, '') AS category_name, COALESCE(meal_types.name, '') AS meal_type_name,
+			foods.rating_count, foods.rating_average`).
+		Joins("LEFT JOIN categories ON categories.id = foods.category_id").
+		Joins("LEFT JOIN meal_types ON meal_types.id = foods.meal_type_id").
+		Order("foods.id")
+
+	return eachRow(query, fn)
+}
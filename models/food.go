@@ -1,18 +1,47 @@
 package models
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
 type Food struct {
-	ID           uint          `gorm:"primaryKey"`
-	Name         string        `json:"name"`
-	Quanity      string        `json:"quanity"`
-	CategoryID   uint          // Foreign key for Category
-	Category     Category      `json:"category"` // Category relationship
-	MealTypeID   uint          // Foreign key for MealType
-	MealType     MealType      `json:"meal_type"` // MealType relationship
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	Model
+	Name         string   `json:"name" binding:"required,max=100"`
+	Quanity      string   `gorm:"column:quantity" json:"quanity" binding:"max=100"`
+	Description  string   `json:"description" binding:"max=1000"`
+	CategoryID   uint     `binding:"required"`                 // Foreign key for Category
+	Category     Category `json:"category" binding:"-"`        // Category relationship
+	MealTypeID   uint     `binding:"required,exists=mealtype"` // Foreign key for MealType
+	MealType     MealType `json:"meal_type" binding:"-"`       // MealType relationship
+	ImageKey     string   `json:"image_key,omitempty"`
+	ThumbnailKey string   `json:"thumbnail_key,omitempty"`
+	// Aggregated from reviews and updated incrementally when a review is added
+	RatingCount   int     `gorm:"not null;default:0" json:"rating_count"`
+	RatingAverage float64 `gorm:"not null;default:0" json:"rating_average"`
+}
+
+var FoodListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":             "foods.id",
+		"name":           "foods.name",
+		"rating_average": "foods.rating_average",
+		"rating_count":   "foods.rating_count",
+	},
+	Filters: map[string]Field{
+		"name":         {"foods.name", FieldString},
+		"category_id":  {"foods.category_id", FieldUint},
+		"meal_type_id": {"foods.meal_type_id", FieldUint},
+	},
+}
+
+var FoodPatchSpec = PatchSpec{
+	"name":        {"name", FieldString, true},
+	"quanity":     {"quantity", FieldString, true},
+	"description": {"description", FieldString, true},
+	"CategoryID":  {"category_id", FieldUint, true},
+	"MealTypeID":  {"meal_type_id", FieldUint, true},
 }
 
 type FoodHandler struct {
@@ -23,28 +52,56 @@ func NewFoodHandler(db *gorm.DB) *FoodHandler {
 	return &FoodHandler{db}
 }
 
-func (f *FoodHandler) CreateFood(food *Food) error {
-	return f.db.Create(food).Error
+func (f *FoodHandler) CreateFood(ctx context.Context, food *Food) error {
+	_, err := audited[Food](f.db.WithContext(ctx), AuditCreate, &food.ID, func(tx *gorm.DB) error {
+		return tx.Omit("RatingCount", "RatingAverage").Create(food).Error
+	})
+	return err
 }
 
-func (f *FoodHandler) GetFood(id uint) (*Food, error) {
+func (f *FoodHandler) GetFood(ctx context.Context, id uint) (*Food, error) {
 	var food Food
-	result := f.db.First(&food, id)
-	return &food, result.Error
+	result := f.db.WithContext(ctx).First(&food, id)
+	return &food, notFound(result.Error, "food")
+}
+
+func (f *FoodHandler) GetFoods(ctx context.Context, q *ListQuery) (*Page[Food], error) {
+	return paginate(f.db.WithContext(ctx).Model(&Food{}), q, "foods.id", func(food *Food) uint { return food.ID })
+}
+
+// UpdateFood writes food over the food id if it is still at version, and
+// sets the new ID and version on food.
+func (f *FoodHandler) UpdateFood(ctx context.Context, id uint, version Version, food *Food) error {
+	_, err := audited[Food](f.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&Food{}).Where("id = ? AND version = ?", id, version).Omit("RatingCount", "RatingAverage").Updates(food)
+		return checkVersion(tx, result, &Food{}, id, "food")
+	})
+	if err != nil {
+		return err
+	}
+	food.ID, food.Version = id, version+1
+	return nil
 }
 
-func (f *FoodHandler) GetFoods() ([]Food, error) {
-	var foods []Food
-	result := f.db.Find(&foods)
-	return foods, result.Error
+// PatchFood writes changes to the food id if it is still at version.
+func (f *FoodHandler) PatchFood(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*Food, error) {
+	return patchRow[Food](f.db.WithContext(ctx), id, version, changes, "food")
 }
 
-func (f *FoodHandler) UpdateFood(id uint, food *Food) error {
-	result := f.db.Model(&Food{}).Where("id = ?", id).Updates(food)
-	return result.Error
+func (f *FoodHandler) DeleteFood(ctx context.Context, id uint, version Version) error {
+	_, err := audited[Food](f.db.WithContext(ctx), AuditDelete, &id, func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", version).Delete(&Food{}, id)
+		return checkVersion(tx, result, &Food{}, id, "food")
+	})
+	return err
 }
 
-func (f *FoodHandler) DeleteFood(id uint) error {
-	result := f.db.Delete(&Food{}, id)
-	return result.Error
+func (f *FoodHandler) SetFoodImage(ctx context.Context, id uint, imageKey, thumbnailKey string) error {
+	_, err := audited[Food](f.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		return tx.Model(&Food{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"image_key":     imageKey,
+			"thumbnail_key": thumbnailKey,
+		}).Error
+	})
+	return err
 }
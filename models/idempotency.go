@@ -0,0 +1,112 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrIdempotencyMismatch   = &Error{Kind: KindValidation, Code: "idempotency_key_reused", Message: "idempotency key was used with a different request"}
+	ErrIdempotencyInProgress = &Error{Kind: KindConflict, Code: "idempotency_key_in_progress", Message: "a request with this idempotency key is still in progress"}
+)
+
+// IdempotencyKey stores the response of a mutating request so that a retry
+// with the same Idempotency-Key header replays it instead of running the
+// request again. Keys are scoped to the user that sent them. A StatusCode of
+// zero marks a request that has not finished yet, which holds the key until
+// LockedUntil.
+type IdempotencyKey struct {
+	UserID      uint   `gorm:"primaryKey;autoIncrement:false"`
+	Key         string `gorm:"primaryKey"`
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+	LockedUntil *time.Time
+}
+
+type IdempotencyHandler struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyHandler(db *gorm.DB) *IdempotencyHandler {
+	return &IdempotencyHandler{db}
+}
+
+// Begin claims key for a request with the given hash. It returns nil when
+// the request should run, or the stored key when it already completed and
+// its response should be replayed. Reusing a key for a different request
+// returns ErrIdempotencyMismatch, and retrying while the first request is
+// still running returns ErrIdempotencyInProgress.
+//
+// A request holds the key until the deadline of ctx, or for ttl without one.
+// A request that never completed or released its key, because the server
+// stopped while running it, no longer holds it after that, and the next retry
+// takes the key over and runs the request again.
+func (h *IdempotencyHandler) Begin(ctx context.Context, userID uint, key, requestHash string, ttl time.Duration) (*IdempotencyKey, error) {
+	now := time.Now()
+	if err := h.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&IdempotencyKey{}).Error; err != nil {
+		return nil, err
+	}
+
+	lockedUntil := now.Add(ttl)
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(lockedUntil) {
+		lockedUntil = deadline
+	}
+	claim := IdempotencyKey{UserID: userID, Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: now.Add(ttl), LockedUntil: &lockedUntil}
+	result := h.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&claim)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var stored IdempotencyKey
+	if err := h.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	switch {
+	case stored.RequestHash != requestHash:
+		return nil, ErrIdempotencyMismatch
+	case stored.StatusCode == 0:
+		return nil, h.takeOver(ctx, userID, key, now, lockedUntil)
+	}
+	return &stored, nil
+}
+
+// takeOver claims a key whose request has not finished once its lock has
+// passed. Keys stored before locks were recorded have none and can be taken
+// over right away. Only one of several concurrent retries succeeds, the
+// others get ErrIdempotencyInProgress.
+func (h *IdempotencyHandler) takeOver(ctx context.Context, userID uint, key string, now, lockedUntil time.Time) error {
+	result := h.db.WithContext(ctx).Model(&IdempotencyKey{}).
+		Where("user_id = ? AND key = ? AND status_code = 0", userID, key).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Update("locked_until", lockedUntil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrIdempotencyInProgress
+	}
+	return nil
+}
+
+// Complete records the response of the request that claimed key.
+func (h *IdempotencyHandler) Complete(ctx context.Context, userID uint, key string, statusCode int, contentType string, body []byte) error {
+	return h.db.WithContext(ctx).Model(&IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", userID, key).
+		Updates(map[string]interface{}{"status_code": statusCode, "content_type": contentType, "body": body}).Error
+}
+
+// Release forgets key so the request can be retried, used when it failed
+// without a response worth replaying.
+func (h *IdempotencyHandler) Release(ctx context.Context, userID uint, key string) error {
+	return h.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).Delete(&IdempotencyKey{}).Error
+}
@@ -0,0 +1,61 @@
+package models_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+)
+
+func TestIdempotencyLock(t *testing.T) {
+	db := memoryDB(t)
+	keys := models.NewIdempotencyHandler(db)
+	ctx := context.Background()
+
+	// the first request runs until its deadline, then the server stops
+	running, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if stored, err := keys.Begin(running, 1, "key", "hash", time.Hour); err != nil || stored != nil {
+		t.Fatalf("Begin() = %v, %v, want to run the request", stored, err)
+	}
+	if _, err := keys.Begin(ctx, 1, "key", "hash", time.Hour); !errors.Is(err, models.ErrIdempotencyInProgress) {
+		t.Errorf("Begin() while running = %v, want ErrIdempotencyInProgress", err)
+	}
+	if _, err := keys.Begin(ctx, 1, "key", "other", time.Hour); !errors.Is(err, models.ErrIdempotencyMismatch) {
+		t.Errorf("Begin() with another request = %v, want ErrIdempotencyMismatch", err)
+	}
+
+	<-running.Done()
+	if stored, err := keys.Begin(ctx, 1, "key", "hash", time.Hour); err != nil || stored != nil {
+		t.Fatalf("Begin() after the deadline = %v, %v, want to take the key over", stored, err)
+	}
+	if _, err := keys.Begin(ctx, 1, "key", "hash", time.Hour); !errors.Is(err, models.ErrIdempotencyInProgress) {
+		t.Errorf("Begin() after the takeover = %v, want ErrIdempotencyInProgress", err)
+	}
+
+	if err := keys.Complete(ctx, 1, "key", 201, "application/json", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := keys.Begin(ctx, 1, "key", "hash", time.Hour); err != nil || stored == nil || stored.StatusCode != 201 {
+		t.Errorf("Begin() after Complete = %+v, %v, want the stored response", stored, err)
+	}
+}
+
+func TestIdempotencyUnlockedKey(t *testing.T) {
+	db := memoryDB(t)
+	keys := models.NewIdempotencyHandler(db)
+	ctx := context.Background()
+
+	// keys claimed before locks were recorded
+	if _, err := keys.Begin(ctx, 1, "key", "hash", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("UPDATE idempotency_keys SET locked_until = NULL").Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := keys.Begin(ctx, 1, "key", "hash", time.Hour); err != nil || stored != nil {
+		t.Errorf("Begin() = %v, %v, want to take the key over", stored, err)
+	}
+}
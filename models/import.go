@@ -0,0 +1,374 @@
+package models
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	ImportUsers = "users"
+	ImportFoods = "foods"
+	ImportSides = "sides"
+	ImportMenus = "menus"
+)
+
+var (
+	ErrUnknownImport = &Error{Kind: KindValidation, Code: "unknown_import", Message: "unknown import resource, expected users, foods, sides or menus"}
+	ErrInvalidCSV    = &Error{Kind: KindValidation, Code: "invalid_csv", Message: "invalid CSV file"}
+)
+
+// errRollback aborts the import transaction without reporting a failure.
+var errRollback = errors.New("import rolled back")
+
+// importColumns lists the required CSV columns of every importable resource.
+// Users may additionally carry a password column.
+var importColumns = map[string][]string{
+	ImportUsers: {"name", "email", "telephone", "role"},
+	ImportFoods: {"name", "quantity", "category", "meal_type"},
+	ImportSides: {"name", "quantity"},
+	ImportMenus: {"meal_type", "category", "food", "quantity"},
+}
+
+type ImportError struct {
+	Row     int    `json:"row" example:"3"`
+	Field   string `json:"field,omitempty" example:"email"`
+	Message string `json:"message" example:"invalid email address"`
+}
+
+type ImportReport struct {
+	Resource  string        `json:"resource" example:"users"`
+	DryRun    bool          `json:"dry_run"`
+	Committed bool          `json:"committed"`
+	Rows      int           `json:"rows" example:"120"`
+	Created   int           `json:"created" example:"100"`
+	Updated   int           `json:"updated" example:"20"`
+	Errors    []ImportError `json:"errors"`
+}
+
+type ImportHandler struct {
+	db *gorm.DB
+}
+
+func NewImportHandler(db *gorm.DB) *ImportHandler {
+	return &ImportHandler{db}
+}
+
+// importRow is one CSV record addressed by column name.
+type importRow struct {
+	line   int
+	fields map[string]string
+}
+
+func (r importRow) get(column string) string {
+	return strings.TrimSpace(r.fields[column])
+}
+
+// Import reads resource rows from a CSV file with a header line and upserts
+// them by their natural key (email for users, name for everything else).
+// All rows are written inside one transaction which is only committed when
+// every row is valid and dryRun is false, so a bad file changes nothing.
+func (h *ImportHandler) Import(ctx context.Context, resource string, r io.Reader, dryRun bool) (*ImportReport, error) {
+	required, ok := importColumns[resource]
+	if !ok {
+		return nil, ErrUnknownImport
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for _, column := range required {
+		if !contains(header, column) {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, column)
+		}
+	}
+
+	report := &ImportReport{Resource: resource, DryRun: dryRun, Errors: []ImportError{}}
+
+	err = h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		importer := &importer{ctx: ctx, tx: tx, report: report}
+
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+			}
+
+			report.Rows++
+			row := importRow{line: report.Rows + 1, fields: map[string]string{}}
+			for i, value := range record {
+				row.fields[header[i]] = value
+			}
+
+			if err := importer.importRow(resource, row); err != nil {
+				return err
+			}
+		}
+
+		if dryRun || len(report.Errors) > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+
+	report.Committed = err == nil
+	return report, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type importer struct {
+	ctx    context.Context
+	tx     *gorm.DB
+	report *ImportReport
+}
+
+func (i *importer) fail(row importRow, field, message string) {
+	i.report.Errors = append(i.report.Errors, ImportError{Row: row.line, Field: field, Message: message})
+}
+
+// saved counts a successfully written row.
+func (i *importer) saved(created bool) {
+	if created {
+		i.report.Created++
+	} else {
+		i.report.Updated++
+	}
+}
+
+// importRow validates and writes a single row. Validation problems are
+// recorded in the report; only database failures are returned.
+func (i *importer) importRow(resource string, row importRow) error {
+	switch resource {
+	case ImportUsers:
+		return i.importUser(row)
+	case ImportFoods:
+		return i.importFood(row, false)
+	case ImportSides:
+		return i.importSide(row)
+	case ImportMenus:
+		return i.importFood(row, true)
+	}
+	return ErrUnknownImport
+}
+
+func (i *importer) importUser(row importRow) error {
+	name, email, role, password := row.get("name"), strings.ToLower(row.get("email")), row.get("role"), row.get("password")
+
+	valid := true
+	if name == "" {
+		i.fail(row, "name", "name is required")
+		valid = false
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		i.fail(row, "email", "invalid email address")
+		valid = false
+	}
+	if role == "" {
+		role = "user"
+	}
+	if role != "user" && role != "admin" {
+		i.fail(row, "role", "role must be user or admin")
+		valid = false
+	}
+	if !valid {
+		return nil
+	}
+
+	var user User
+	err := i.tx.Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if password == "" {
+			i.fail(row, "password", "password is required for new users")
+			return nil
+		}
+		user = User{Name: name, Email: email, Telephone: row.get("telephone"), Role: role, Password: password}
+		if err := NewUserHandler(i.tx).CreateUser(i.ctx, &user); err != nil {
+			return err
+		}
+		i.saved(true)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"name":      name,
+		"telephone": row.get("telephone"),
+		"role":      role,
+	}
+	if password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		updates["password"] = string(hashedPassword)
+	}
+	_, err = audited[User](i.tx, AuditUpdate, &user.ID, func(tx *gorm.DB) error {
+		return tx.Model(&user).Updates(updates).Error
+	})
+	if err != nil {
+		return err
+	}
+	i.saved(false)
+	return nil
+}
+
+func (i *importer) importSide(row importRow) error {
+	name := row.get("name")
+	if name == "" {
+		i.fail(row, "name", "name is required")
+		return nil
+	}
+
+	var side Sides
+	err := i.tx.Where("name = ?", name).First(&side).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		side = Sides{Name: name, Quantity: row.get("quantity")}
+		if err := NewSidesHandler(i.tx).CreateSides(i.ctx, &side); err != nil {
+			return err
+		}
+		i.saved(true)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = audited[Sides](i.tx, AuditUpdate, &side.ID, func(tx *gorm.DB) error {
+		return tx.Model(&side).Update("quantity", row.get("quantity")).Error
+	})
+	if err != nil {
+		return err
+	}
+	i.saved(false)
+	return nil
+}
+
+// importFood upserts a food referencing its category and meal type by name.
+// Food rows require both to exist, menu rows create missing ones on the fly.
+func (i *importer) importFood(row importRow, menu bool) error {
+	nameColumn := "name"
+	if menu {
+		nameColumn = "food"
+	}
+	name, categoryName, mealTypeName := row.get(nameColumn), row.get("category"), row.get("meal_type")
+
+	valid := true
+	if name == "" {
+		i.fail(row, nameColumn, nameColumn+" is required")
+		valid = false
+	}
+	if categoryName == "" {
+		i.fail(row, "category", "category is required")
+		valid = false
+	}
+	if mealTypeName == "" {
+		i.fail(row, "meal_type", "meal_type is required")
+		valid = false
+	}
+	if !valid {
+		return nil
+	}
+
+	var category Category
+	found, err := i.findByName(&category, categoryName, menu, &Category{Name: categoryName})
+	if err != nil {
+		return err
+	}
+	if !found {
+		i.fail(row, "category", fmt.Sprintf("category %q does not exist", categoryName))
+		valid = false
+	}
+
+	var mealType MealType
+	found, err = i.findByName(&mealType, mealTypeName, menu, &MealType{Name: mealTypeName})
+	if err != nil {
+		return err
+	}
+	if !found {
+		i.fail(row, "meal_type", fmt.Sprintf("meal type %q does not exist", mealTypeName))
+		valid = false
+	}
+	if !valid {
+		return nil
+	}
+
+	var food Food
+	err = i.tx.Where("name = ?", name).First(&food).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		food = Food{Name: name, Quanity: row.get("quantity"), CategoryID: category.ID, MealTypeID: mealType.ID}
+		if err := NewFoodHandler(i.tx).CreateFood(i.ctx, &food); err != nil {
+			return err
+		}
+		i.saved(true)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = audited[Food](i.tx, AuditUpdate, &food.ID, func(tx *gorm.DB) error {
+		return tx.Model(&food).Updates(map[string]interface{}{
+			"quantity":     row.get("quantity"),
+			"category_id":  category.ID,
+			"meal_type_id": mealType.ID,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	i.saved(false)
+	return nil
+}
+
+// findByName loads the record named name into dest. When create is set a
+// missing record is created from fallback and loaded into dest instead.
+func (i *importer) findByName(dest interface{}, name string, create bool, fallback interface{}) (bool, error) {
+	err := i.tx.Where("name = ?", name).First(dest).Error
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if !create {
+		return false, nil
+	}
+
+	if err := i.tx.Create(fallback).Error; err != nil {
+		return false, err
+	}
+	if err := i.tx.First(dest, "name = ?", name).Error; err != nil {
+		return false, err
+	}
+	return true, appendAudit(i.tx, AuditCreate, nil, dest)
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+var ErrInvalidQuery = &Error{Kind: KindValidation, Code: "invalid_query", Message: "invalid query"}
+
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldUint
+	// FieldOptionalUint is a uint column that may be NULL, such as an
+	// optional reference
+	FieldOptionalUint
+	FieldBool
+	FieldTime
+)
+
+// Field is a column that can be filtered on, with the type of its values.
+type Field struct {
+	Column string
+	Type   FieldType
+}
+
+// ListSpec whitelists the sort keys and filters a list endpoint accepts,
+// mapping the query parameter names to columns.
+type ListSpec struct {
+	Sorts   map[string]string
+	Filters map[string]Field
+}
+
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+type Filter struct {
+	Column string
+	Value  interface{}
+}
+
+// ListQuery is a parsed and validated list request.
+type ListQuery struct {
+	Limit   int
+	Page    int
+	Cursor  uint
+	Sorts   []Sort
+	Filters []Filter
+}
+
+type PageMeta struct {
+	Total      int64  `json:"total" example:"120"`
+	Limit      int    `json:"limit" example:"50"`
+	Page       int    `json:"page,omitempty" example:"1"`
+	NextCursor string `json:"next_cursor,omitempty" example:"MTI"`
+}
+
+// Page is the envelope every list endpoint responds with.
+type Page[T any] struct {
+	Data []T      `json:"data"`
+	Meta PageMeta `json:"meta"`
+}
+
+// Parse reads limit, page or cursor, sort and the whitelisted filters from
+// values. Sort keys may be prefixed with "-" for descending order and are
+// separated by commas. Cursors are only accepted when sorting by id, which is
+// also the default order.
+func (s ListSpec) Parse(values url.Values) (*ListQuery, error) {
+	q := &ListQuery{Limit: DefaultListLimit, Page: 1}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxListLimit {
+			return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxListLimit)
+		}
+		q.Limit = n
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		for _, key := range strings.Split(sort, ",") {
+			desc := strings.HasPrefix(key, "-")
+			column, ok := s.Sorts[strings.TrimPrefix(key, "-")]
+			if !ok {
+				return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, key)
+			}
+			q.Sorts = append(q.Sorts, Sort{Column: column, Desc: desc})
+		}
+	}
+
+	cursor, page := values.Get("cursor"), values.Get("page")
+	switch {
+	case cursor != "" && page != "":
+		return nil, fmt.Errorf("%w: page and cursor cannot be combined", ErrInvalidQuery)
+	case cursor != "":
+		if len(q.Sorts) > 1 || (len(q.Sorts) == 1 && q.Sorts[0].Column != s.Sorts["id"]) {
+			return nil, fmt.Errorf("%w: cursor can only be used when sorting by id", ErrInvalidQuery)
+		}
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+		}
+		q.Cursor = id
+		q.Page = 0
+	case page != "":
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: page must be a positive number", ErrInvalidQuery)
+		}
+		q.Page = n
+	}
+
+	for name, field := range s.Filters {
+		raw, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		var err error
+		switch field.Type {
+		case FieldUint, FieldOptionalUint:
+			value, err = strconv.ParseUint(raw[0], 10, 64)
+		case FieldBool:
+			value, err = strconv.ParseBool(raw[0])
+		default:
+			value = raw[0]
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid value for %s", ErrInvalidQuery, name)
+		}
+		q.Filters = append(q.Filters, Filter{Column: field.Column, Value: value})
+	}
+
+	return q, nil
+}
+
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	return uint(id), err
+}
+
+// filter applies the filters of q to query.
+func (q *ListQuery) filter(query *gorm.DB) *gorm.DB {
+	for _, f := range q.Filters {
+		query = query.Where(f.Column+" = ?", f.Value)
+	}
+	return query
+}
+
+// order applies the sort order of q to query, using idColumn as tie breaker so
+// pages are stable.
+func (q *ListQuery) order(query *gorm.DB, idColumn string) *gorm.DB {
+	sortedByID := false
+	for _, s := range q.Sorts {
+		direction := " ASC"
+		if s.Desc {
+			direction = " DESC"
+		}
+		query = query.Order(s.Column + direction)
+		sortedByID = sortedByID || s.Column == idColumn
+	}
+	if !sortedByID {
+		query = query.Order(idColumn + " ASC")
+	}
+	return query
+}
+
+// descByID reports whether the keyset for cursors runs backwards.
+func (q *ListQuery) descByID() bool {
+	return len(q.Sorts) == 1 && q.Sorts[0].Desc
+}
+
+// paginate runs query, which must have its model set, for one page of q.
+// idOf returns the primary key of an item and is used to build the cursor of
+// the next page. The preloads are only loaded for the returned page.
+func paginate[T any](query *gorm.DB, q *ListQuery, idColumn string, idOf func(*T) uint, preloads ...string) (*Page[T], error) {
+	query = q.filter(query).Session(&gorm.Session{})
+
+	page := &Page[T]{Data: []T{}, Meta: PageMeta{Limit: q.Limit, Page: q.Page}}
+	if err := query.Count(&page.Meta.Total).Error; err != nil {
+		return nil, err
+	}
+
+	find := q.order(query, idColumn).Limit(q.Limit + 1)
+	for _, preload := range preloads {
+		find = find.Preload(preload)
+	}
+	if q.Cursor != 0 {
+		if q.descByID() {
+			find = find.Where(idColumn+" < ?", q.Cursor)
+		} else {
+			find = find.Where(idColumn+" > ?", q.Cursor)
+		}
+	} else {
+		find = find.Offset((q.Page - 1) * q.Limit)
+	}
+
+	if err := find.Find(&page.Data).Error; err != nil {
+		return nil, err
+	}
+
+	// One extra row was fetched to find out whether there is a next page
+	if len(page.Data) > q.Limit {
+		page.Data = page.Data[:q.Limit]
+		if len(q.Sorts) == 0 || (len(q.Sorts) == 1 && q.Sorts[0].Column == idColumn) {
+			page.Meta.NextCursor = encodeCursor(idOf(&page.Data[q.Limit-1]))
+		}
+	}
+
+	return page, nil
+}
@@ -1,12 +1,29 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 type MealType struct {
-	ID         uint   `gorm:"primaryKey"`
-	Name       string `json:"name"`
-	Foods      []Food `gorm:"foreignKey:MealTypeID"`
-	gorm.Model `json:"-" swaggerignore:"true"`
+	Model
+	Name  string `json:"name" binding:"required,max=100"`
+	Foods []Food `gorm:"foreignKey:MealTypeID"`
+}
+
+var MealTypeListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":   "meal_types.id",
+		"name": "meal_types.name",
+	},
+	Filters: map[string]Field{
+		"name": {"meal_types.name", FieldString},
+	},
+}
+
+var MealTypePatchSpec = PatchSpec{
+	"name": {"name", FieldString, true},
 }
 
 type MealTypeHandler struct {
@@ -17,28 +34,46 @@ func NewMealTypeHandler(db *gorm.DB) *MealTypeHandler {
 	return &MealTypeHandler{db}
 }
 
-func (h *MealTypeHandler) CreateMealType(mealType *MealType) error {
-	return h.db.Create(mealType).Error
+func (h *MealTypeHandler) CreateMealType(ctx context.Context, mealType *MealType) error {
+	_, err := audited[MealType](h.db.WithContext(ctx), AuditCreate, &mealType.ID, func(tx *gorm.DB) error {
+		return tx.Create(mealType).Error
+	})
+	return err
 }
 
-func (h *MealTypeHandler) GetMealType(id uint) (*MealType, error) {
+func (h *MealTypeHandler) GetMealType(ctx context.Context, id uint) (*MealType, error) {
 	var mealType MealType
-	result := h.db.First(&mealType, id)
-	return &mealType, result.Error
+	result := h.db.WithContext(ctx).First(&mealType, id)
+	return &mealType, notFound(result.Error, "meal type")
+}
+
+func (h *MealTypeHandler) GetMealTypes(ctx context.Context, q *ListQuery) (*Page[MealType], error) {
+	return paginate(h.db.WithContext(ctx).Model(&MealType{}), q, "meal_types.id", func(mealType *MealType) uint { return mealType.ID })
 }
 
-func (h *MealTypeHandler) GetMealTypes() ([]MealType, error) {
-	var mealTypes []MealType
-	result := h.db.Find(&mealTypes)
-	return mealTypes, result.Error
+// UpdateMealType writes mealType over the meal type id if it is still at
+// version, and sets the new ID and version on mealType.
+func (h *MealTypeHandler) UpdateMealType(ctx context.Context, id uint, version Version, mealType *MealType) error {
+	_, err := audited[MealType](h.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&MealType{}).Where("id = ? AND version = ?", id, version).Updates(mealType)
+		return checkVersion(tx, result, &MealType{}, id, "meal type")
+	})
+	if err != nil {
+		return err
+	}
+	mealType.ID, mealType.Version = id, version+1
+	return nil
 }
 
-func (h *MealTypeHandler) UpdateMealType(id uint, mealType *MealType) error {
-	result := h.db.Model(&MealType{}).Where("id = ?", id).Updates(mealType)
-	return result.Error
+// PatchMealType writes changes to the meal type id if it is still at version.
+func (h *MealTypeHandler) PatchMealType(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*MealType, error) {
+	return patchRow[MealType](h.db.WithContext(ctx), id, version, changes, "meal type")
 }
 
-func (h *MealTypeHandler) DeleteMealType(id uint) error {
-	result := h.db.Delete(&MealType{}, id)
-	return result.Error
+func (h *MealTypeHandler) DeleteMealType(ctx context.Context, id uint, version Version) error {
+	_, err := audited[MealType](h.db.WithContext(ctx), AuditDelete, &id, func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", version).Delete(&MealType{}, id)
+		return checkVersion(tx, result, &MealType{}, id, "meal type")
+	})
+	return err
 }
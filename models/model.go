@@ -0,0 +1,117 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrVersionMismatch is returned when a row changed since the version the
+// caller last read.
+var ErrVersionMismatch = &Error{Kind: KindPrecondition, Code: "version_mismatch", Message: "the resource was modified by another request"}
+
+// Model holds the columns shared by every table. It replaces gorm.Model,
+// whose ID clashed with the primary keys the models declared themselves, and
+// keeps the bookkeeping timestamps out of API responses.
+type Model struct {
+	ID        uint           `gorm:"primaryKey"`
+	Version   Version        `gorm:"not null;default:1" json:"version" example:"1"`
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
+}
+
+// BeforeCreate starts every row at version 1, whatever the client sent.
+func (m *Model) BeforeCreate(tx *gorm.DB) error {
+	m.Version = 1
+	return nil
+}
+
+// Version counts the updates of a row and is used for optimistic locking:
+// every UPDATE of a model increments it, and writes that must not overwrite
+// a concurrent change filter on the version the client last read.
+type Version uint
+
+func (v Version) Value() (driver.Value, error) {
+	return int64(v), nil
+}
+
+func (v *Version) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case int64:
+		*v = Version(src)
+	case []byte:
+		n, err := strconv.ParseUint(string(src), 10, 64)
+		*v = Version(n)
+		return err
+	default:
+		return fmt.Errorf("cannot scan %T into Version", src)
+	}
+	return nil
+}
+
+// UpdateClauses makes GORM increment the version on every update.
+func (Version) UpdateClauses(field *schema.Field) []clause.Interface {
+	return []clause.Interface{versionIncrement{field}}
+}
+
+type versionIncrement struct {
+	field *schema.Field
+}
+
+func (versionIncrement) Name() string               { return "" }
+func (versionIncrement) Build(clause.Builder)       {}
+func (versionIncrement) MergeClause(*clause.Clause) {}
+
+func (v versionIncrement) ModifyStatement(stmt *gorm.Statement) {
+	if stmt.SQL.Len() > 0 {
+		return
+	}
+
+	var set clause.Set
+	if c, ok := stmt.Clauses["SET"]; ok {
+		set, _ = c.Expression.(clause.Set)
+	} else {
+		set = callbacks.ConvertToAssignments(stmt)
+	}
+	if len(set) == 0 {
+		return
+	}
+
+	// the version is never taken from the values being written
+	assignments := make(clause.Set, 0, len(set)+1)
+	for _, assignment := range set {
+		if assignment.Column.Name != v.field.DBName {
+			assignments = append(assignments, assignment)
+		}
+	}
+	assignments = append(assignments, clause.Assignment{
+		Column: clause.Column{Name: v.field.DBName},
+		Value:  clause.Expr{SQL: stmt.Quote(v.field.DBName) + " + 1"},
+	})
+	stmt.AddClause(assignments)
+}
+
+// checkVersion returns the error of a write filtered on the id and version
+// of a row. When the write matched nothing, it tells whether the row is gone
+// or was changed by someone else. resource names the model in errors.
+func checkVersion(tx, result *gorm.DB, model interface{}, id uint, resource string) error {
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	var count int64
+	if err := tx.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return notFound(gorm.ErrRecordNotFound, resource)
+	}
+	return ErrVersionMismatch
+}
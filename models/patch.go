@@ -0,0 +1,193 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	ErrInvalidPatch   = &Error{Kind: KindValidation, Code: "invalid_patch", Message: "invalid patch"}
+	ErrPatchForbidden = &Error{Kind: KindForbidden, Code: "patch_forbidden", Message: "patch changes fields only admins may change"}
+)
+
+// PatchField is a field a PATCH request may write.
+type PatchField struct {
+	Column    string
+	Type      FieldType
+	AdminOnly bool
+}
+
+// PatchSpec whitelists the fields of a resource a PATCH request may write,
+// keyed by their name in the JSON representation. Fields that are not listed,
+// such as IDs, versions and aggregates, cannot be patched.
+type PatchSpec map[string]PatchField
+
+// MergePatch turns an RFC 7396 merge patch into the column values to write.
+// Only the fields present in the patch are written, and null resets a field
+// to its zero value, or to NULL when it is optional. Patching fields that are not in the spec, or that are
+// admin only when admin is false, is an error.
+func (s PatchSpec) MergePatch(patch []byte, admin bool) (map[string]interface{}, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: a merge patch must be a JSON object", ErrInvalidPatch)
+	}
+	return s.changes(fields, admin)
+}
+
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// JSONPatch turns an RFC 6902 JSON patch into the column values to write. The
+// resources are flat, so only the add, replace and remove operations on
+// top level fields are supported. Remove resets a field to its zero value.
+func (s PatchSpec) JSONPatch(patch []byte, admin bool) (map[string]interface{}, error) {
+	var ops []jsonPatchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: a JSON patch must be an array of operations", ErrInvalidPatch)
+	}
+
+	fields := make(map[string]json.RawMessage, len(ops))
+	for _, op := range ops {
+		name, ok := strings.CutPrefix(op.Path, "/")
+		if !ok || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("%w: cannot patch path %q", ErrInvalidPatch, op.Path)
+		}
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: %s of %q needs a value", ErrInvalidPatch, op.Op, op.Path)
+			}
+			fields[name] = op.Value
+		case "remove":
+			fields[name] = json.RawMessage("null")
+		default:
+			return nil, fmt.Errorf("%w: unsupported operation %q", ErrInvalidPatch, op.Op)
+		}
+	}
+	return s.changes(fields, admin)
+}
+
+// changes validates the patched fields and decodes their values.
+func (s PatchSpec) changes(fields map[string]json.RawMessage, admin bool) (map[string]interface{}, error) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := make(map[string]interface{}, len(fields))
+	for _, name := range names {
+		field, ok := s[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q cannot be patched", ErrInvalidPatch, name)
+		}
+		if field.AdminOnly && !admin {
+			return nil, fmt.Errorf("%w: %s", ErrPatchForbidden, name)
+		}
+
+		value, err := field.decode(fields[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid value for %s", ErrInvalidPatch, name)
+		}
+		changes[field.Column] = value
+	}
+	return changes, nil
+}
+
+// decode reads a JSON value of the field type, where null is the zero value,
+// or NULL for optional fields.
+func (f PatchField) decode(raw json.RawMessage) (interface{}, error) {
+	null := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+	var err error
+	switch f.Type {
+	case FieldUint:
+		var value uint
+		if !null {
+			err = json.Unmarshal(raw, &value)
+		}
+		return value, err
+	case FieldOptionalUint:
+		if null {
+			return nil, nil
+		}
+		var value uint
+		return value, json.Unmarshal(raw, &value)
+	case FieldBool:
+		var value bool
+		if !null {
+			err = json.Unmarshal(raw, &value)
+		}
+		return value, err
+	case FieldTime:
+		var value time.Time
+		if !null {
+			err = json.Unmarshal(raw, &value)
+		}
+		return value, err
+	default:
+		var value string
+		if !null {
+			err = json.Unmarshal(raw, &value)
+		}
+		return value, err
+	}
+}
+
+// schemas caches the parsed schemas of the resources Apply writes to.
+var schemas sync.Map
+
+// Apply writes the column values of a patch onto row, a pointer to the
+// resource the patch was made for, and returns the names of the struct fields
+// it changed, so the patched resource can be validated before it is stored.
+func Apply(row interface{}, changes map[string]interface{}) ([]string, error) {
+	s, err := schema.Parse(row, &schemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(row))
+	fields := make([]string, 0, len(changes))
+	for column, change := range changes {
+		field := s.LookUpField(column)
+		if field == nil {
+			return nil, fmt.Errorf("%s has no column %s", s.Name, column)
+		}
+		if err := field.Set(context.Background(), value, change); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field.Name)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// patchRow writes changes to the row id of T if it is still at version and
+// returns the updated row, recording the change in the audit log. resource
+// names T in errors.
+func patchRow[T any](db *gorm.DB, id uint, version Version, changes map[string]interface{}, resource string) (*T, error) {
+	return audited[T](db, auditAction(changes), &id, func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if len(changes) == 0 {
+			// an empty patch changes nothing but must still match the version
+			result = tx.Model(new(T)).Where("id = ? AND version = ?", id, version).Limit(1).Find(new(T))
+		} else {
+			result = tx.Model(new(T)).Where("id = ? AND version = ?", id, version).Updates(changes)
+		}
+		return checkVersion(tx, result, new(T), id, resource)
+	})
+}
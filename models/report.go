@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ReportItemFood = "food"
+	ReportItemSide = "side"
+)
+
+// ProductionRow is the number of portions of one dish to prepare for a meal
+// type on a given day.
+type ProductionRow struct {
+	Date      string `json:"date" example:"2024-05-01"`
+	MealType  string `json:"meal_type" example:"Lunch"`
+	ItemType  string `json:"item_type" example:"food"`
+	ItemID    uint   `json:"item_id" example:"1"`
+	ItemName  string `json:"item_name" example:"Kebab"`
+	Portions  int64  `json:"portions" example:"120"`
+	Reserved  int64  `json:"reserved" example:"80"`
+	Served    int64  `json:"served" example:"40"`
+	Cancelled int64  `json:"cancelled" example:"3"`
+}
+
+type ReportHandler struct {
+	db *gorm.DB
+}
+
+func NewReportHandler(db *gorm.DB) *ReportHandler {
+	return &ReportHandler{db}
+}
+
+// productionCounts runs over deleted reservations too, since cancelling one
+// deletes it. Reservations deleted before cancelling set their status are
+// still reserved, they count as cancelled as well. Served reservations keep
+// their status when deleted and still count, the portions were handed out.
+const productionCounts = `COUNT(CASE WHEN reservations.status = 'served' OR (reservations.deleted_at IS NULL AND reservations.status = 'reserved') THEN 1 END) AS portions,
+	COUNT(CASE WHEN reservations.deleted_at IS NULL AND reservations.status = 'reserved' THEN 1 END) AS reserved,
+	COUNT(CASE WHEN reservations.status = 'served' THEN 1 END) AS served,
+	COUNT(CASE WHEN reservations.status = 'cancelled' OR (reservations.deleted_at IS NOT NULL AND reservations.status = 'reserved') THEN 1 END) AS cancelled`
+
+// Production aggregates the reservations between startDate and endDate (both
+// days inclusive, zero means unbounded) per day, meal type and dish. The
+// counting happens in the database so reservations are never loaded.
+func (h *ReportHandler) Production(ctx context.Context, startDate, endDate time.Time) ([]ProductionRow, error) {
+	var foods, sides []ProductionRow
+
+	foodQuery := h.productionQuery(ctx, startDate, endDate).
+		Select("DATE(reservations.date) AS date, COALESCE(meal_types.name, '') AS meal_type, '" + ReportItemFood + "' AS item_type, foods.id AS item_id, foods.name AS item_name, " + productionCounts).
+		Group("DATE(reservations.date), meal_types.name, foods.id, foods.name")
+	if err := foodQuery.Scan(&foods).Error; err != nil {
+		return nil, err
+	}
+
+	sideQuery := h.productionQuery(ctx, startDate, endDate).
+		Joins("JOIN sides ON sides.id = reservations.side_id").
+		Select("DATE(reservations.date) AS date, COALESCE(meal_types.name, '') AS meal_type, '" + ReportItemSide + "' AS item_type, sides.id AS item_id, sides.name AS item_name, " + productionCounts).
+		Group("DATE(reservations.date), meal_types.name, sides.id, sides.name")
+	if err := sideQuery.Scan(&sides).Error; err != nil {
+		return nil, err
+	}
+
+	rows := append(foods, sides...)
+	for i := range rows {
+		// Drivers return DATE() either as a date or as a timestamp string
+		if len(rows[i].Date) > len("2006-01-02") {
+			rows[i].Date = rows[i].Date[:len("2006-01-02")]
+		}
+	}
+	return rows, nil
+}
+
+func (h *ReportHandler) productionQuery(ctx context.Context, startDate, endDate time.Time) *gorm.DB {
+	query := h.db.WithContext(ctx).Unscoped().Model(&Reservation{}).
+		Joins("JOIN foods ON foods.id = reservations.food_id").
+		Joins("LEFT JOIN meal_types ON meal_types.id = foods.meal_type_id").
+		Order("date, meal_type, item_name")
+
+	if !startDate.IsZero() {
+		query = query.Where("reservations.date >= ?", startDate)
+	}
+
+	if !endDate.IsZero() {
+		query = query.Where("reservations.date < ?", endDate.AddDate(0, 0, 1))
+	}
+
+	return query
+}
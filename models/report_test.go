@@ -0,0 +1,54 @@
+package models_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+)
+
+func TestProductionCountsDeletedServed(t *testing.T) {
+	db := memoryDB(t)
+	ctx := context.Background()
+	if _, err := models.NewImportHandler(db).Import(ctx, models.ImportMenus, strings.NewReader("meal_type,category,food,quantity\nLunch,Main,Kebab,1\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	user := models.User{Name: "A", Email: "a@example.com", Password: "password123"}
+	if err := models.NewUserHandler(db).CreateUser(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	reservations := models.NewReservationHandler(db)
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		reservation := models.Reservation{FoodID: 1, UserID: user.ID, Date: time.Now()}
+		if err := reservations.Reserve(ctx, &reservation); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, reservation.ID)
+	}
+	// the first is served and then deleted, the second cancelled, the third
+	// still reserved
+	if err := reservations.MarkServed(ctx, ids[0], 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := reservations.DeleteReservation(ctx, ids[0], 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := reservations.DeleteReservation(ctx, ids[1], 1); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := models.NewReportHandler(db).Production(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("Production() = %+v, want one row for the food", rows)
+	}
+	if row := rows[0]; row.Portions != 2 || row.Served != 1 || row.Reserved != 1 || row.Cancelled != 1 {
+		t.Errorf("Production() = %+v, want 2 portions, 1 served, 1 reserved and 1 cancelled", row)
+	}
+}
@@ -1,23 +1,54 @@
 package models
 
 import (
-	"errors"
+	"context"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	ReservationReserved  = "reserved"
+	ReservationServed    = "served"
+	ReservationCancelled = "cancelled"
+)
+
 type Reservation struct {
-	ID         uint      `gorm:"primaryKey"`
-	FoodID     uint      // Foreign key for Food
-	Food       Food      `json:"food"` // Food relationship
-	UserID     uint      // Foreign key for User
-	User       User      `json:"user"` // User relationship
-	SideID     uint      // Foreign key for Sides
-	Side       Sides     `json:"side"` // Sides relationship
-	Date       time.Time `json:"date"`
-	IsPaid     bool      `json:"-"`
-	gorm.Model `json:"-" swaggerignore:"true"`
+	Model
+	FoodID uint      `binding:"required,exists=food"` // Foreign key for Food
+	Food   Food      `json:"food" binding:"-"`        // Food relationship
+	UserID uint      // Foreign key for User
+	User   User      `json:"user" binding:"-"`         // User relationship
+	SideID *uint     `binding:"omitempty,exists=side"` // Foreign key for Sides, nil without a side dish
+	Side   Sides     `json:"side" binding:"-"`         // Sides relationship
+	Date   time.Time `json:"date" binding:"required,notpast"`
+	IsPaid bool      `json:"-"`
+	Status string    `gorm:"default:reserved" json:"status" example:"reserved"`
+}
+
+var ReservationListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":     "reservations.id",
+		"date":   "reservations.date",
+		"status": "reservations.status",
+	},
+	Filters: map[string]Field{
+		"user_id": {"reservations.user_id", FieldUint},
+		"food_id": {"reservations.food_id", FieldUint},
+		"side_id": {"reservations.side_id", FieldUint},
+		"status":  {"reservations.status", FieldString},
+		"is_paid": {"reservations.is_paid", FieldBool},
+	},
+}
+
+// ReservationPatchSpec leaves out the status, which only changes through
+// transitions such as MarkServed.
+var ReservationPatchSpec = PatchSpec{
+	"FoodID":  {"food_id", FieldUint, false},
+	"SideID":  {"side_id", FieldOptionalUint, false},
+	"date":    {"date", FieldTime, false},
+	"UserID":  {"user_id", FieldUint, true},
+	"is_paid": {"is_paid", FieldBool, true},
 }
 
 type ReservationHandler struct {
@@ -28,59 +59,187 @@ func NewReservationHandler(db *gorm.DB) *ReservationHandler {
 	return &ReservationHandler{db}
 }
 
-func (r *ReservationHandler) Reserve(reservation *Reservation) error {
-	return r.db.Create(reservation).Error
+func (r *ReservationHandler) Reserve(ctx context.Context, reservation *Reservation) error {
+	reservation.Status = ReservationReserved
+	_, err := audited[Reservation](r.db.WithContext(ctx), AuditCreate, &reservation.ID, func(tx *gorm.DB) error {
+		return tx.Create(reservation).Error
+	})
+	return err
 }
 
-func (r *ReservationHandler) DeleteReservation(id uint) error {
-	result := r.db.Delete(&Reservation{}, id)
-	return result.Error
+// ReservationMealType returns the name of the meal type a reservation is for,
+// also after the reservation was deleted.
+func (r *ReservationHandler) ReservationMealType(ctx context.Context, id uint) (string, error) {
+	var name string
+	result := r.db.WithContext(ctx).Table("reservations").
+		Select("meal_types.name").
+		Joins("JOIN foods ON foods.id = reservations.food_id").
+		Joins("JOIN meal_types ON meal_types.id = foods.meal_type_id").
+		Where("reservations.id = ?", id).
+		Limit(1).
+		Scan(&name)
+	return name, result.Error
 }
 
-func (r *ReservationHandler) UpdateReservation(id uint, reservation *Reservation) error {
-	result := r.db.Model(&Reservation{}).Where("id = ?", id).Updates(reservation)
-	return result.Error
+// DeleteReservation cancels the reservation id if it is still at version and
+// moves it to the trash. Reservations that were already served keep their
+// status.
+func (r *ReservationHandler) DeleteReservation(ctx context.Context, id uint, version Version) error {
+	_, err := audited[Reservation](r.db.WithContext(ctx), AuditDelete, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&Reservation{}).Where("id = ? AND version = ?", id, version).Updates(map[string]interface{}{
+			"status":     gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", ReservationReserved, ReservationCancelled),
+			"deleted_at": time.Now(),
+		})
+		return checkVersion(tx, result, &Reservation{}, id, "reservation")
+	})
+	return err
+}
+
+// UpdateReservation writes reservation over the reservation id if it is still
+// at version, and sets the new ID and version on reservation.
+func (r *ReservationHandler) UpdateReservation(ctx context.Context, id uint, version Version, reservation *Reservation) error {
+	_, err := audited[Reservation](r.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		// The status is only changed through dedicated transitions such as MarkServed
+		result := tx.Model(&Reservation{}).Where("id = ? AND version = ?", id, version).Omit("Status").Updates(reservation)
+		return checkVersion(tx, result, &Reservation{}, id, "reservation")
+	})
+	if err != nil {
+		return err
+	}
+	reservation.ID, reservation.Version = id, version+1
+	return nil
+}
+
+// PatchReservation writes changes to the reservation id if it is still at
+// version.
+func (r *ReservationHandler) PatchReservation(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*Reservation, error) {
+	return patchRow[Reservation](r.db.WithContext(ctx), id, version, changes, "reservation")
+}
+
+func (r *ReservationHandler) MarkServed(ctx context.Context, id uint, version Version) error {
+	_, err := audited[Reservation](r.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&Reservation{}).Where("id = ? AND version = ?", id, version).Update("status", ReservationServed)
+		return checkVersion(tx, result, &Reservation{}, id, "reservation")
+	})
+	return err
+}
+
+// BlacklistThreshold is the number of unpaid reservations a user may have
+// before they are blacklisted.
+const BlacklistThreshold = 3
+
+// BlacklistEntry is a blacklisted user with their counted unpaid reservations.
+type BlacklistEntry struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Unpaid int64  `json:"unpaid"`
+}
+
+// unpaidReservations selects the unpaid reservations that count toward the
+// blacklist, which excludes those made before the user's last lift.
+func unpaidReservations(query *gorm.DB) *gorm.DB {
+	return query.
+		Joins("JOIN users ON users.id = reservations.user_id").
+		Where("reservations.is_paid = ?", false).
+		Where("users.blacklist_lifted_at IS NULL OR reservations.created_at > users.blacklist_lifted_at")
 }
 
-func (r *ReservationHandler) IsBlackListed(id uint) error {
+func (r *ReservationHandler) IsBlackListed(ctx context.Context, id uint) error {
 	var count int64
-	result := r.db.Model(&Reservation{}).Where("user_id = ? AND is_paid = ?", id, false).Count(&count)
+	result := unpaidReservations(r.db.WithContext(ctx).Model(&Reservation{})).Where("reservations.user_id = ?", id).Count(&count)
 
-	if count > 3 {
-		return errors.New("User is blacklisted due to having more than 3 unpaid reservations")
+	if count > BlacklistThreshold {
+		return ErrBlacklisted
 	}
 
 	return result.Error
 }
 
-func (r *ReservationHandler) ListReservations(startDate, endDate time.Time) ([]Reservation, error) {
-	var reservations []Reservation
-	query := r.db
+func (r *ReservationHandler) ListBlacklisted(ctx context.Context) ([]BlacklistEntry, error) {
+	var entries []BlacklistEntry
+	result := unpaidReservations(r.db.WithContext(ctx).Model(&Reservation{})).
+		Select("users.id AS user_id, users.name, users.email, COUNT(reservations.id) AS unpaid").
+		Where("users.deleted_at IS NULL").
+		Group("users.id, users.name, users.email").
+		Having("COUNT(reservations.id) > ?", BlacklistThreshold).
+		Order("users.id").
+		Scan(&entries)
+	return entries, result.Error
+}
 
+// MarkPaid marks the given reservations as paid and returns how many were
+// updated.
+func (r *ReservationHandler) MarkPaid(ctx context.Context, ids []uint) (int64, error) {
+	return markPaid(r.db.WithContext(ctx), "id IN ?", ids)
+}
+
+// MarkUserPaid marks every unpaid reservation of a user as paid and returns
+// how many were updated.
+func (r *ReservationHandler) MarkUserPaid(ctx context.Context, userID uint) (int64, error) {
+	return markPaid(r.db.WithContext(ctx), "user_id = ?", userID)
+}
+
+// markPaid marks the unpaid reservations matching the condition as paid, with
+// an audit entry for each, and returns how many were updated.
+func markPaid(db *gorm.DB, condition string, args ...interface{}) (int64, error) {
+	var count int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var unpaid []Reservation
+		if err := tx.Where(condition, args...).Where("is_paid = ?", false).Order("id").Find(&unpaid).Error; err != nil {
+			return err
+		}
+		for i := range unpaid {
+			before := &unpaid[i]
+			result := tx.Model(&Reservation{}).Where("id = ? AND is_paid = ?", before.ID, false).Update("is_paid", true)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			after, err := findAudited[Reservation](tx, before.ID)
+			if err != nil {
+				return err
+			}
+			if err := appendAudit(tx, AuditPayment, before, after); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func filterReservationDates(query *gorm.DB, startDate, endDate time.Time) *gorm.DB {
 	if !startDate.IsZero() {
-		query = query.Where("date >= ?", startDate)
+		query = query.Where("reservations.date >= ?", startDate)
 	}
 
 	if !endDate.IsZero() {
-		query = query.Where("date <= ?", endDate)
+		query = query.Where("reservations.date <= ?", endDate)
 	}
 
-	result := query.Preload("User").Preload("Food").Preload("Side").Find(&reservations)
-	return reservations, result.Error
+	return query
 }
 
-func (r *ReservationHandler) GetReservation(id uint) (*Reservation, error) {
-	var reservation Reservation
-	result := r.db.First(&reservation, id)
-	return &reservation, result.Error
+func (r *ReservationHandler) ListReservations(ctx context.Context, startDate, endDate time.Time, q *ListQuery) (*Page[Reservation], error) {
+	query := filterReservationDates(r.db.WithContext(ctx).Model(&Reservation{}), startDate, endDate)
+	return paginate(query, q, "reservations.id", reservationID, "User", "Food", "Side")
+}
+
+func reservationID(reservation *Reservation) uint {
+	return reservation.ID
 }
 
-func (r *ReservationHandler) GetReservationsByUserID(userID uint) ([]Reservation, error) {
-	var reservations []Reservation
-	result := r.db.Preload("User").Preload("Restaurant").Where("user_id = ?", userID).Find(&reservations)
+func (r *ReservationHandler) GetReservation(ctx context.Context, id uint) (*Reservation, error) {
+	var reservation Reservation
+	result := r.db.WithContext(ctx).First(&reservation, id)
+	return &reservation, notFound(result.Error, "reservation")
+}
 
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return reservations, nil
+func (r *ReservationHandler) GetReservationsByUserID(ctx context.Context, userID uint, q *ListQuery) (*Page[Reservation], error) {
+	query := r.db.WithContext(ctx).Model(&Reservation{}).Where("reservations.user_id = ?", userID)
+	return paginate(query, q, "reservations.id", reservationID, "User", "Food", "Side")
 }
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRating      = &Error{Kind: KindValidation, Code: "invalid_rating", Message: "ratings must be between 1 and 5"}
+	ErrReviewNotAllowed   = &Error{Kind: KindForbidden, Code: "review_not_allowed", Message: "only the owner of a reservation can review it"}
+	ErrReservationNotDone = &Error{Kind: KindConflict, Code: "reservation_not_served", Message: "only served reservations can be reviewed"}
+	ErrAlreadyReviewed    = &Error{Kind: KindConflict, Code: "already_reviewed", Message: "reservation has already been reviewed"}
+)
+
+type Review struct {
+	Model
+	ReservationID uint   `gorm:"uniqueIndex" json:"reservation_id"`
+	UserID        uint   `json:"user_id"`
+	FoodID        uint   `json:"food_id"`
+	SideID        uint   `json:"side_id"`
+	FoodRating    int    `json:"food_rating" example:"5"`
+	SideRating    int    `json:"side_rating" example:"4"`
+	Comment       string `json:"comment" example:"Tasty and warm"`
+	Hidden        bool   `json:"hidden"`
+}
+
+type ReviewHandler struct {
+	db *gorm.DB
+}
+
+func NewReviewHandler(db *gorm.DB) *ReviewHandler {
+	return &ReviewHandler{db}
+}
+
+func validRating(rating int) bool {
+	return rating >= 1 && rating <= 5
+}
+
+// ratingUpdate folds a new rating into the stored average without
+// recomputing it from all reviews.
+func ratingUpdate(rating int) map[string]interface{} {
+	return map[string]interface{}{
+		"rating_average": gorm.Expr("(rating_average * rating_count + ?) / (rating_count + 1)", rating),
+		"rating_count":   gorm.Expr("rating_count + 1"),
+	}
+}
+
+// CreateReview stores the review of a served reservation for the given user
+// and updates the rating aggregates of the reviewed food and side.
+func (h *ReviewHandler) CreateReview(ctx context.Context, userID, reservationID uint, review *Review) error {
+	return h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservation Reservation
+		if err := tx.First(&reservation, reservationID).Error; err != nil {
+			return notFound(err, "reservation")
+		}
+
+		if reservation.UserID != userID {
+			return ErrReviewNotAllowed
+		}
+		if reservation.Status != ReservationServed {
+			return ErrReservationNotDone
+		}
+
+		if !validRating(review.FoodRating) {
+			return ErrInvalidRating
+		}
+		if reservation.SideID == nil {
+			review.SideRating = 0
+		} else if !validRating(review.SideRating) {
+			return ErrInvalidRating
+		}
+
+		var count int64
+		if err := tx.Model(&Review{}).Where("reservation_id = ?", reservationID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyReviewed
+		}
+
+		review.ReservationID = reservation.ID
+		review.UserID = reservation.UserID
+		review.FoodID = reservation.FoodID
+		review.SideID = 0
+		if reservation.SideID != nil {
+			review.SideID = *reservation.SideID
+		}
+		review.Hidden = false
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Food{}).Where("id = ?", review.FoodID).Updates(ratingUpdate(review.FoodRating)).Error; err != nil {
+			return err
+		}
+		if review.SideID != 0 {
+			if err := tx.Model(&Sides{}).Where("id = ?", review.SideID).Updates(ratingUpdate(review.SideRating)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetFoodReviews returns the visible reviews of a food.
+func (h *ReviewHandler) GetFoodReviews(ctx context.Context, foodID uint) ([]Review, error) {
+	var reviews []Review
+	result := h.db.WithContext(ctx).Where("food_id = ? AND hidden = ?", foodID, false).Order("id DESC").Find(&reviews)
+	return reviews, result.Error
+}
+
+// GetSideReviews returns the visible reviews of a side dish.
+func (h *ReviewHandler) GetSideReviews(ctx context.Context, sideID uint) ([]Review, error) {
+	var reviews []Review
+	result := h.db.WithContext(ctx).Where("side_id = ? AND hidden = ?", sideID, false).Order("id DESC").Find(&reviews)
+	return reviews, result.Error
+}
+
+// GetReviews lists all reviews for moderation, optionally filtered by visibility.
+func (h *ReviewHandler) GetReviews(ctx context.Context, hidden *bool) ([]Review, error) {
+	var reviews []Review
+	query := h.db.WithContext(ctx).Order("id DESC")
+	if hidden != nil {
+		query = query.Where("hidden = ?", *hidden)
+	}
+	result := query.Find(&reviews)
+	return reviews, result.Error
+}
+
+// SetReviewHidden hides or shows the comment of a review. Hidden reviews still
+// count towards the rating aggregates.
+func (h *ReviewHandler) SetReviewHidden(ctx context.Context, id uint, version Version, hidden bool) error {
+	db := h.db.WithContext(ctx)
+	result := db.Model(&Review{}).Where("id = ? AND version = ?", id, version).Update("hidden", hidden)
+	return checkVersion(db, result, &Review{}, id, "review")
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SearchFood = "food"
+	SearchSide = "side"
+	SearchUser = "user"
+)
+
+type SearchResult struct {
+	Type    string  `json:"type" example:"food"`
+	ID      uint    `json:"id" example:"1"`
+	Title   string  `json:"title" example:"Kebab"`
+	Snippet string  `json:"snippet" example:"Grilled <mark>kebab</mark> with saffron rice"` // HTML escaped, with matches in <mark> tags
+	Rank    float64 `json:"rank" example:"0.6"`
+}
+
+type SearchHandler struct {
+	db *gorm.DB
+}
+
+func NewSearchHandler(db *gorm.DB) *SearchHandler {
+	return &SearchHandler{db}
+}
+
+// searchTarget describes how one resource is searched. document is the SQL
+// expression the full-text index is built from, fuzzy the condition used when
+// full-text search finds nothing and like the condition used on databases
+// other than PostgreSQL.
+type searchTarget struct {
+	model    interface{}
+	title    string
+	document string
+	fuzzy    string
+	like     string
+}
+
+var searchTargets = map[string]searchTarget{
+	SearchFood: {
+		model:    &Food{},
+		title:    "foods.name",
+		document: "foods.name || ' ' || COALESCE(foods.description, '')",
+		fuzzy:    "(foods.name % @query OR foods.name ILIKE @like OR foods.description ILIKE @like)",
+		like:     `(foods.name LIKE @like ESCAPE '\' OR foods.description LIKE @like ESCAPE '\')`,
+	},
+	SearchSide: {
+		model:    &Sides{},
+		title:    "sides.name",
+		document: "sides.name || ' ' || COALESCE(sides.description, '')",
+		fuzzy:    "(sides.name % @query OR sides.name ILIKE @like OR sides.description ILIKE @like)",
+		like:     `(sides.name LIKE @like ESCAPE '\' OR sides.description LIKE @like ESCAPE '\')`,
+	},
+	SearchUser: {
+		model:    &User{},
+		title:    "users.name",
+		document: "COALESCE(users.name, '') || ' ' || COALESCE(users.email, '') || ' ' || COALESCE(users.telephone, '')",
+		fuzzy:    "(users.name % @query OR users.email ILIKE @like OR users.telephone ILIKE @like)",
+		like:     `(users.name LIKE @like ESCAPE '\' OR users.email LIKE @like ESCAPE '\' OR users.telephone LIKE @like ESCAPE '\')`,
+	},
+}
+
+// Search looks up query in the given resource types using PostgreSQL
+// full-text search, falling back to trigram similarity for a type when
+// full-text search finds nothing, e.g. for typos or partial words. Results of
+// all types are merged by rank. Soft deleted rows are never returned.
+//
+// Other databases only support a case-insensitive substring match, ranking
+// titles that start with the query first.
+func (h *SearchHandler) Search(ctx context.Context, query string, types []string, limit int) ([]SearchResult, error) {
+	results := []SearchResult{}
+	postgres := h.db.Dialector.Name() == "postgres"
+
+	for _, t := range types {
+		target, ok := searchTargets[t]
+		if !ok {
+			continue
+		}
+
+		if !postgres {
+			found, err := h.like(ctx, t, target, query, limit)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, found...)
+			continue
+		}
+
+		found, err := h.fullText(ctx, t, target, query, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			found, err = h.fuzzy(ctx, t, target, query, limit)
+			if err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, found...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (h *SearchHandler) fullText(ctx context.Context, t string, target searchTarget, query string, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+	tsQuery := "plainto_tsquery('simple', @query)"
+	document := "to_tsvector('simple', " + target.document + ")"
+
+	err := h.db.WithContext(ctx).Model(target.model).
+		Select("'"+t+"' AS type, id, "+target.title+" AS title, "+
+			"ts_headline('simple', "+escapeHTML(target.document)+", "+tsQuery+", 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS snippet, "+
+			"ts_rank("+document+", "+tsQuery+") AS rank", map[string]interface{}{"query": query}).
+		Where(document+" @@ "+tsQuery, map[string]interface{}{"query": query}).
+		Order("rank DESC").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
+
+func (h *SearchHandler) fuzzy(ctx context.Context, t string, target searchTarget, query string, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+	args := map[string]interface{}{
+		"query": query,
+		"like":  "%" + escapeLike(query) + "%",
+	}
+
+	err := h.db.WithContext(ctx).Model(target.model).
+		Select("'"+t+"' AS type, id, "+target.title+" AS title, "+escapeHTML(target.title)+" AS snippet, "+
+			"similarity("+target.title+", @query) AS rank", args).
+		Where(target.fuzzy, args).
+		Order("rank DESC").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
+
+func (h *SearchHandler) like(ctx context.Context, t string, target searchTarget, query string, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+	args := map[string]interface{}{
+		"like":   "%" + escapeLike(query) + "%",
+		"prefix": escapeLike(query) + "%",
+	}
+
+	err := h.db.WithContext(ctx).Model(target.model).
+		Select("'"+t+"' AS type, id, "+target.title+" AS title, "+escapeHTML(target.title)+" AS snippet, "+
+			"CASE WHEN "+target.title+` LIKE @prefix ESCAPE '\' THEN 1.0 ELSE 0.5 END AS rank`, args).
+		Where(target.like, args).
+		Order("rank DESC").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
+
+// escapeHTML wraps the SQL expression expr so it yields its text escaped for
+// HTML, like html.EscapeString. Snippets are escaped before ts_headline adds
+// its tags, so the stored text cannot inject markup of its own.
+func escapeHTML(expr string) string {
+	for _, r := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&#34;"}, {"'", "&#39;"}} {
+		expr = "REPLACE(" + expr + ", '" + strings.ReplaceAll(r[0], "'", "''") + "', '" + r[1] + "')"
+	}
+	return expr
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
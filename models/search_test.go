@@ -0,0 +1,35 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+)
+
+func TestSearchEscapesSnippets(t *testing.T) {
+	db := memoryDB(t)
+	ctx := context.Background()
+	food := models.Food{Name: `Kebab <img src=x onerror="alert('x')"> & rice`, CategoryID: 1, MealTypeID: 1}
+	if err := db.Create(&models.Category{Name: "Main"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.MealType{Name: "Lunch"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&food).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := models.NewSearchHandler(db).Search(ctx, "kebab", []string{models.SearchFood}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Search() = %+v, want the food", results)
+	}
+	want := "Kebab &lt;img src=x onerror=&#34;alert(&#39;x&#39;)&#34;&gt; &amp; rice"
+	if results[0].Snippet != want || results[0].Title != food.Name {
+		t.Errorf("result = %+v, want the snippet %q", results[0], want)
+	}
+}
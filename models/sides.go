@@ -1,12 +1,39 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 type Sides struct {
-	ID           uint          `gorm:"primaryKey"`
-	Name         string        `json:"name"`
-	Quantity     string        `json:"quantity"`
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	Model
+	Name         string `json:"name" binding:"required,max=100"`
+	Quantity     string `json:"quantity" binding:"max=100"`
+	Description  string `json:"description" binding:"max=1000"`
+	ImageKey     string `json:"image_key,omitempty"`
+	ThumbnailKey string `json:"thumbnail_key,omitempty"`
+	// Aggregated from reviews and updated incrementally when a review is added
+	RatingCount   int     `gorm:"not null;default:0" json:"rating_count"`
+	RatingAverage float64 `gorm:"not null;default:0" json:"rating_average"`
+}
+
+var SidesListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":             "sides.id",
+		"name":           "sides.name",
+		"rating_average": "sides.rating_average",
+		"rating_count":   "sides.rating_count",
+	},
+	Filters: map[string]Field{
+		"name": {"sides.name", FieldString},
+	},
+}
+
+var SidesPatchSpec = PatchSpec{
+	"name":        {"name", FieldString, true},
+	"quantity":    {"quantity", FieldString, true},
+	"description": {"description", FieldString, true},
 }
 
 type SidesHandler struct {
@@ -17,28 +44,56 @@ func NewSidesHandler(db *gorm.DB) *SidesHandler {
 	return &SidesHandler{db}
 }
 
-func (h *SidesHandler) CreateSides(sides *Sides) error {
-	return h.db.Create(sides).Error
+func (h *SidesHandler) CreateSides(ctx context.Context, sides *Sides) error {
+	_, err := audited[Sides](h.db.WithContext(ctx), AuditCreate, &sides.ID, func(tx *gorm.DB) error {
+		return tx.Omit("RatingCount", "RatingAverage").Create(sides).Error
+	})
+	return err
 }
 
-func (h *SidesHandler) GetSide(id uint) (*Sides, error) {
+func (h *SidesHandler) GetSide(ctx context.Context, id uint) (*Sides, error) {
 	var sides Sides
-	result := h.db.First(&sides, id)
-	return &sides, result.Error
+	result := h.db.WithContext(ctx).First(&sides, id)
+	return &sides, notFound(result.Error, "side dish")
+}
+
+func (h *SidesHandler) GetSides(ctx context.Context, q *ListQuery) (*Page[Sides], error) {
+	return paginate(h.db.WithContext(ctx).Model(&Sides{}), q, "sides.id", func(side *Sides) uint { return side.ID })
+}
+
+// UpdateSides writes sides over the side id if it is still at version, and
+// sets the new ID and version on sides.
+func (h *SidesHandler) UpdateSides(ctx context.Context, id uint, version Version, sides *Sides) error {
+	_, err := audited[Sides](h.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&Sides{}).Where("id = ? AND version = ?", id, version).Omit("RatingCount", "RatingAverage").Updates(sides)
+		return checkVersion(tx, result, &Sides{}, id, "side dish")
+	})
+	if err != nil {
+		return err
+	}
+	sides.ID, sides.Version = id, version+1
+	return nil
 }
 
-func (h *SidesHandler) GetSides() ([]Sides, error) {
-	var sides []Sides
-	result := h.db.Find(&sides)
-	return sides, result.Error
+// PatchSides writes changes to the side id if it is still at version.
+func (h *SidesHandler) PatchSides(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*Sides, error) {
+	return patchRow[Sides](h.db.WithContext(ctx), id, version, changes, "side dish")
 }
 
-func (h *SidesHandler) UpdateSides(id uint, sides *Sides) error {
-	result := h.db.Model(&Sides{}).Where("id = ?", id).Updates(sides)
-	return result.Error
+func (h *SidesHandler) DeleteSides(ctx context.Context, id uint, version Version) error {
+	_, err := audited[Sides](h.db.WithContext(ctx), AuditDelete, &id, func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", version).Delete(&Sides{}, id)
+		return checkVersion(tx, result, &Sides{}, id, "side dish")
+	})
+	return err
 }
 
-func (h *SidesHandler) DeleteSides(id uint) error {
-	result := h.db.Delete(&Sides{}, id)
-	return result.Error
+func (h *SidesHandler) SetSidesImage(ctx context.Context, id uint, imageKey, thumbnailKey string) error {
+	_, err := audited[Sides](h.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		return tx.Model(&Sides{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"image_key":     imageKey,
+			"thumbnail_key": thumbnailKey,
+		}).Error
+	})
+	return err
 }
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// The interfaces below describe what the API and command line need from each
+// handler, so they can be replaced with fakes in tests.
+
+type UserStore interface {
+	CreateUser(ctx context.Context, user *User) error
+	CheckPassword(ctx context.Context, email, password string) bool
+	GetUser(ctx context.Context, id uint) (*User, error)
+	GetUsers(ctx context.Context, q *ListQuery) (*Page[User], error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	UpdateUser(ctx context.Context, id uint, version Version, user *User) error
+	PatchUser(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*User, error)
+	DeleteUser(ctx context.Context, id uint, version Version) error
+	ResetPassword(ctx context.Context, id uint, password string) error
+	LiftBlacklist(ctx context.Context, id uint) error
+	ExportUsers(ctx context.Context, fn func(*User) error) error
+}
+
+type FoodStore interface {
+	CreateFood(ctx context.Context, food *Food) error
+	GetFood(ctx context.Context, id uint) (*Food, error)
+	GetFoods(ctx context.Context, q *ListQuery) (*Page[Food], error)
+	UpdateFood(ctx context.Context, id uint, version Version, food *Food) error
+	PatchFood(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*Food, error)
+	DeleteFood(ctx context.Context, id uint, version Version) error
+	SetFoodImage(ctx context.Context, id uint, imageKey, thumbnailKey string) error
+	ExportFoods(ctx context.Context, fn func(*FoodExport) error) error
+}
+
+type SidesStore interface {
+	CreateSides(ctx context.Context, sides *Sides) error
+	GetSide(ctx context.Context, id uint) (*Sides, error)
+	GetSides(ctx context.Context, q *ListQuery) (*Page[Sides], error)
+	UpdateSides(ctx context.Context, id uint, version Version, sides *Sides) error
+	PatchSides(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*Sides, error)
+	DeleteSides(ctx context.Context, id uint, version Version) error
+	SetSidesImage(ctx context.Context, id uint, imageKey, thumbnailKey string) error
+}
+
+type MealTypeStore interface {
+	CreateMealType(ctx context.Context, mealType *MealType) error
+	GetMealType(ctx context.Context, id uint) (*MealType, error)
+	GetMealTypes(ctx context.Context, q *ListQuery) (*Page[MealType], error)
+	UpdateMealType(ctx context.Context, id uint, version Version, mealType *MealType) error
+	PatchMealType(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*MealType, error)
+	DeleteMealType(ctx context.Context, id uint, version Version) error
+}
+
+type ReservationStore interface {
+	Reserve(ctx context.Context, reservation *Reservation) error
+	GetReservation(ctx context.Context, id uint) (*Reservation, error)
+	ListReservations(ctx context.Context, startDate, endDate time.Time, q *ListQuery) (*Page[Reservation], error)
+	GetReservationsByUserID(ctx context.Context, userID uint, q *ListQuery) (*Page[Reservation], error)
+	UpdateReservation(ctx context.Context, id uint, version Version, reservation *Reservation) error
+	PatchReservation(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*Reservation, error)
+	DeleteReservation(ctx context.Context, id uint, version Version) error
+	MarkServed(ctx context.Context, id uint, version Version) error
+	ReservationMealType(ctx context.Context, id uint) (string, error)
+	MarkPaid(ctx context.Context, ids []uint) (int64, error)
+	MarkUserPaid(ctx context.Context, userID uint) (int64, error)
+	IsBlackListed(ctx context.Context, id uint) error
+	ListBlacklisted(ctx context.Context) ([]BlacklistEntry, error)
+	ExportReservations(ctx context.Context, startDate, endDate time.Time, q *ListQuery, fn func(*ReservationExport) error) error
+}
+
+type ReviewStore interface {
+	CreateReview(ctx context.Context, userID, reservationID uint, review *Review) error
+	GetFoodReviews(ctx context.Context, foodID uint) ([]Review, error)
+	GetSideReviews(ctx context.Context, sideID uint) ([]Review, error)
+	GetReviews(ctx context.Context, hidden *bool) ([]Review, error)
+	SetReviewHidden(ctx context.Context, id uint, version Version, hidden bool) error
+}
+
+type ReportStore interface {
+	Production(ctx context.Context, startDate, endDate time.Time) ([]ProductionRow, error)
+}
+
+type ImportStore interface {
+	Import(ctx context.Context, resource string, r io.Reader, dryRun bool) (*ImportReport, error)
+}
+
+type SearchStore interface {
+	Search(ctx context.Context, query string, types []string, limit int) ([]SearchResult, error)
+}
+
+type IdempotencyStore interface {
+	Begin(ctx context.Context, userID uint, key, requestHash string, ttl time.Duration) (*IdempotencyKey, error)
+	Complete(ctx context.Context, userID uint, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, userID uint, key string) error
+}
+
+type AuditStore interface {
+	ListAudit(ctx context.Context, since, until time.Time, q *ListQuery) (*Page[AuditEntry], error)
+	VerifyAudit(ctx context.Context) (*AuditVerification, error)
+}
+
+type TrashStore interface {
+	ListTrash(ctx context.Context, resource string, q *ListQuery) (*Page[TrashItem], error)
+	Restore(ctx context.Context, resource string, id uint) error
+	Purge(ctx context.Context, resource string, before time.Time) (int64, error)
+	PurgeAll(ctx context.Context, before time.Time) (map[string]int64, error)
+}
+
+var (
+	_ UserStore        = (*UserHandler)(nil)
+	_ FoodStore        = (*FoodHandler)(nil)
+	_ SidesStore       = (*SidesHandler)(nil)
+	_ MealTypeStore    = (*MealTypeHandler)(nil)
+	_ ReservationStore = (*ReservationHandler)(nil)
+	_ ReviewStore      = (*ReviewHandler)(nil)
+	_ ReportStore      = (*ReportHandler)(nil)
+	_ ImportStore      = (*ImportHandler)(nil)
+	_ SearchStore      = (*SearchHandler)(nil)
+	_ IdempotencyStore = (*IdempotencyHandler)(nil)
+	_ TrashStore       = (*TrashHandler)(nil)
+	_ AuditStore       = (*AuditHandler)(nil)
+)
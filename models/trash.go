@@ -0,0 +1,270 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownResource  = &Error{Kind: KindNotFound, Code: "unknown_resource", Message: "unknown resource"}
+	ErrReferenceDeleted = &Error{Kind: KindConflict, Code: "reference_deleted", Message: "a referenced resource is deleted"}
+)
+
+var TrashListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":         "id",
+		"deleted_at": "deleted_at",
+	},
+}
+
+// trashResource is a soft-deletable model. Resources are listed so that rows
+// referencing others are purged before the rows they reference.
+type trashResource struct {
+	name  string
+	model interface{}
+	// references are the columns pointing at rows of other resources, which
+	// must not be deleted when a row is restored
+	references []trashReference
+	// restored are the columns set besides deleted_at when a row is restored
+	restored map[string]interface{}
+}
+
+type trashReference struct {
+	column   string
+	resource string
+}
+
+var trashResources = []trashResource{
+	{"reviews", &Review{}, []trashReference{{"reservation_id", "reservations"}, {"user_id", "users"}, {"food_id", "foods"}, {"side_id", "sides"}}, nil},
+	// deleting a reservation cancels it, restoring it undoes that
+	{"reservations", &Reservation{}, []trashReference{{"user_id", "users"}, {"food_id", "foods"}, {"side_id", "sides"}}, map[string]interface{}{
+		"status": gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", ReservationCancelled, ReservationReserved),
+	}},
+	{"foods", &Food{}, []trashReference{{"category_id", "categories"}, {"meal_type_id", "mealtypes"}}, nil},
+	{"sides", &Sides{}, nil, nil},
+	{"categories", &Category{}, nil, nil},
+	{"mealtypes", &MealType{}, nil, nil},
+	{"users", &User{}, nil, nil},
+}
+
+// unreferenced limits query, over the rows of the resource, to the rows no
+// row of another resource references, deleted or not, since the foreign keys
+// would keep those from being purged.
+func (r trashResource) unreferenced(db *gorm.DB, query *gorm.DB) (*gorm.DB, error) {
+	table, err := tableName(db, r.model)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range trashResources {
+		for _, ref := range other.references {
+			if ref.resource != r.name {
+				continue
+			}
+			otherTable, err := tableName(db, other.model)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s WHERE %s.%s = %s.id)", otherTable, otherTable, ref.column, table))
+		}
+	}
+	return query, nil
+}
+
+func tableName(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// newModel returns a new, empty row of the resource.
+func (r trashResource) newModel() interface{} {
+	return reflect.New(reflect.TypeOf(r.model).Elem()).Interface()
+}
+
+type TrashHandler struct {
+	db *gorm.DB
+}
+
+func NewTrashHandler(db *gorm.DB) *TrashHandler {
+	return &TrashHandler{db}
+}
+
+// TrashResources returns the names of the resources that can be purged.
+func TrashResources() []string {
+	names := make([]string, len(trashResources))
+	for i, resource := range trashResources {
+		names[i] = resource.name
+	}
+	return names
+}
+
+func findTrashResource(name string) (trashResource, error) {
+	for _, resource := range trashResources {
+		if resource.name == name {
+			return resource, nil
+		}
+	}
+	return trashResource{}, ErrUnknownResource
+}
+
+// TrashItem is a soft-deleted row and when it was deleted.
+type TrashItem struct {
+	ID        uint        `json:"id" example:"3"`
+	DeletedAt time.Time   `json:"deleted_at"`
+	Data      interface{} `gorm:"-" json:"data"`
+}
+
+// ListTrash lists the soft-deleted rows of a resource.
+func (h *TrashHandler) ListTrash(ctx context.Context, resource string, q *ListQuery) (*Page[TrashItem], error) {
+	r, err := findTrashResource(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	db := h.db.WithContext(ctx).Unscoped()
+	query := db.Model(r.model).Where("deleted_at IS NOT NULL")
+	page, err := paginate(query, q, "id", func(item *TrashItem) uint { return item.ID })
+	if err != nil || len(page.Data) == 0 {
+		return page, err
+	}
+
+	ids := make([]uint, len(page.Data))
+	for i, item := range page.Data {
+		ids[i] = item.ID
+	}
+	rows := reflect.New(reflect.SliceOf(reflect.TypeOf(r.model)))
+	if err := db.Find(rows.Interface(), ids).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]interface{}, len(ids))
+	for i := 0; i < rows.Elem().Len(); i++ {
+		row := rows.Elem().Index(i)
+		byID[uint(row.Elem().FieldByName("ID").Uint())] = row.Interface()
+	}
+	for i := range page.Data {
+		page.Data[i].Data = byID[page.Data[i].ID]
+	}
+	return page, nil
+}
+
+// Restore undeletes the row id of a resource. The rows it references must not
+// be deleted, so that for example a food is only restored once its category
+// is.
+func (h *TrashHandler) Restore(ctx context.Context, resource string, id uint) error {
+	r, err := findTrashResource(resource)
+	if err != nil {
+		return err
+	}
+
+	return h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before := r.newModel()
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(before, id).Error; err != nil {
+			return notFound(err, "deleted row")
+		}
+
+		for _, ref := range r.references {
+			var refID uint
+			if err := tx.Unscoped().Model(r.model).Where("id = ?", id).Select(ref.column).Scan(&refID).Error; err != nil {
+				return err
+			}
+			if refID == 0 {
+				continue
+			}
+			target, err := findTrashResource(ref.resource)
+			if err != nil {
+				return err
+			}
+			var deletedAt []gorm.DeletedAt
+			if err := tx.Unscoped().Model(target.model).Where("id = ?", refID).Pluck("deleted_at", &deletedAt).Error; err != nil {
+				return err
+			}
+			switch {
+			case len(deletedAt) == 0:
+				return &Error{Kind: KindConflict, Code: ErrReferenceDeleted.Code, Message: fmt.Sprintf("%s %d was purged and cannot be restored", ref.resource, refID)}
+			case deletedAt[0].Valid:
+				return &Error{Kind: KindConflict, Code: ErrReferenceDeleted.Code, Message: fmt.Sprintf("%s %d is deleted, restore it first", ref.resource, refID)}
+			}
+		}
+
+		columns := map[string]interface{}{"deleted_at": nil}
+		for column, value := range r.restored {
+			columns[column] = value
+		}
+		if err := tx.Unscoped().Model(r.newModel()).Where("id = ?", id).Updates(columns).Error; err != nil {
+			return err
+		}
+		after := r.newModel()
+		if err := tx.First(after, id).Error; err != nil {
+			return err
+		}
+		return appendAudit(tx, AuditRestore, before, after)
+	})
+}
+
+// Purge permanently deletes rows of a resource that were soft-deleted before
+// the given time and returns how many were removed. Rows that are still
+// referenced, such as a food with reservations, are kept until the rows
+// referencing them are purged. Each removed row is recorded in the audit log.
+func (h *TrashHandler) Purge(ctx context.Context, resource string, before time.Time) (int64, error) {
+	r, err := findTrashResource(resource)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	err = h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query, err := r.unreferenced(tx, tx.Unscoped().Model(r.model).Where("deleted_at IS NOT NULL AND deleted_at < ?", before))
+		if err != nil {
+			return err
+		}
+		var ids []uint
+		if err := query.Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// The rows are loaded for their audit entries before they are gone
+		rows := reflect.New(reflect.SliceOf(reflect.TypeOf(r.model)))
+		if err := tx.Unscoped().Find(rows.Interface(), ids).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Delete(r.model, ids)
+		if result.Error != nil {
+			return result.Error
+		}
+		for i := 0; i < rows.Elem().Len(); i++ {
+			if err := appendAudit(tx, AuditPurge, rows.Elem().Index(i).Interface(), nil); err != nil {
+				return err
+			}
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// PurgeAll purges every resource in one transaction and returns the number of
+// removed rows per resource.
+func (h *TrashHandler) PurgeAll(ctx context.Context, before time.Time) (map[string]int64, error) {
+	purged := make(map[string]int64, len(trashResources))
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		trash := NewTrashHandler(tx)
+		for _, resource := range trashResources {
+			count, err := trash.Purge(ctx, resource.name, before)
+			if err != nil {
+				return err
+			}
+			purged[resource.name] = count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
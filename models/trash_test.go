@@ -0,0 +1,57 @@
+package models_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+)
+
+func TestPurgeKeepsReferencedRows(t *testing.T) {
+	db := memoryDB(t)
+	ctx := context.Background()
+	if _, err := models.NewImportHandler(db).Import(ctx, models.ImportMenus, strings.NewReader("meal_type,category,food,quantity\nLunch,Main,Kebab,1\nLunch,Main,Soup,1\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := models.NewImportHandler(db).Import(ctx, models.ImportSides, strings.NewReader("name,quantity\nRice,1\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	user := models.User{Name: "A", Email: "a@example.com", Password: "password123"}
+	if err := models.NewUserHandler(db).CreateUser(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+	side := uint(1)
+	reservation := models.Reservation{FoodID: 1, SideID: &side, UserID: user.ID, Date: time.Now()}
+	if err := models.NewReservationHandler(db).Reserve(ctx, &reservation); err != nil {
+		t.Fatal(err)
+	}
+	// Kebab is reserved, Soup is not
+	if err := db.Delete(&models.Food{}, []uint{1, 2}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	trash := models.NewTrashHandler(db)
+	later := time.Now().Add(time.Second)
+	if n, err := trash.Purge(ctx, "foods", later); err != nil || n != 1 {
+		t.Fatalf("Purge(foods) = %d, %v, want the unreserved food purged", n, err)
+	}
+	var left []uint
+	db.Unscoped().Model(&models.Food{}).Pluck("id", &left)
+	if len(left) != 1 || left[0] != 1 {
+		t.Errorf("foods left = %v, want the reserved food 1", left)
+	}
+
+	// once its reservation is purged, the food goes with it
+	if err := models.NewReservationHandler(db).DeleteReservation(ctx, reservation.ID, reservation.Version); err != nil {
+		t.Fatal(err)
+	}
+	purged, err := trash.PurgeAll(ctx, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged["reservations"] != 1 || purged["foods"] != 1 {
+		t.Errorf("PurgeAll() = %v, want a reservation and a food", purged)
+	}
+}
@@ -1,21 +1,68 @@
 package models
 
 import (
-	"fmt"
+	"context"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID           uint          `gorm:"primaryKey"`
-	Name         string        `json:"name"`
-	Email        string        `json:"email"`
-	Telephone    string        `json:"telephone"`
-	Role         string        `json:"role"`
-	Password     string        `json:"password"`
+	Model
+	Name         string        `json:"name" binding:"required,max=100"`
+	Email        string        `json:"email" binding:"required,email"`
+	Telephone    string        `json:"telephone" binding:"omitempty,phone"`
+	Role         string        `json:"role" binding:"omitempty,oneof=user admin"`
+	Password     string        `json:"-"` // bcrypt hash, never sent in responses
 	Reservations []Reservation `gorm:"foreignKey:UserID"`
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	// Unpaid reservations made before this time do not count toward the blacklist
+	BlacklistLiftedAt *time.Time `json:"-"`
+}
+
+// UserDetails are the fields users may change on their own record. The role
+// is only changed by admins, and the password through ResetPassword.
+type UserDetails struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Email     string `json:"email" binding:"required,email"`
+	Telephone string `json:"telephone" binding:"omitempty,phone"`
+}
+
+// AdminUserDetails are the fields admins may change on any user. Passwords are
+// only changed through ResetPassword, so they are always hashed.
+type AdminUserDetails struct {
+	UserDetails
+	Role string `json:"role" binding:"omitempty,oneof=user admin"`
+}
+
+// NewUser is a user created by an admin, with the password to hash.
+type NewUser struct {
+	AdminUserDetails
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+var UserListSpec = ListSpec{
+	Sorts: map[string]string{
+		"id":         "users.id",
+		"name":       "users.name",
+		"email":      "users.email",
+		"role":       "users.role",
+		"created_at": "users.created_at",
+	},
+	Filters: map[string]Field{
+		"email":     {"users.email", FieldString},
+		"telephone": {"users.telephone", FieldString},
+		"role":      {"users.role", FieldString},
+	},
+}
+
+// UserPatchSpec leaves out the password, which is changed through
+// ResetPassword so it is hashed.
+var UserPatchSpec = PatchSpec{
+	"name":      {"name", FieldString, false},
+	"email":     {"email", FieldString, false},
+	"telephone": {"telephone", FieldString, false},
+	"role":      {"role", FieldString, true},
 }
 
 type UserHandler struct {
@@ -26,51 +73,103 @@ func NewUserHandler(db *gorm.DB) *UserHandler {
 	return &UserHandler{db}
 }
 
-func (h *UserHandler) CreateUser(user *User) error {
+func (h *UserHandler) CreateUser(ctx context.Context, user *User) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
-	return h.db.Create(user).Error
+	_, err = audited[User](h.db.WithContext(ctx), AuditCreate, &user.ID, func(tx *gorm.DB) error {
+		return tx.Create(user).Error
+	})
+	return err
 }
 
-func (h *UserHandler) CheckPassword(email, password string) bool {
+func (h *UserHandler) CheckPassword(ctx context.Context, email, password string) bool {
 	var user User
-	if err := h.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := h.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		return false
 	}
 	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	return err == nil
 }
 
-func (h *UserHandler) GetUser(id uint) (*User, error) {
+func (h *UserHandler) GetUser(ctx context.Context, id uint) (*User, error) {
 	var user User
-	result := h.db.First(&user, id)
-	return &user, result.Error
+	result := h.db.WithContext(ctx).First(&user, id)
+	return &user, notFound(result.Error, "user")
+}
+
+func (h *UserHandler) GetUsers(ctx context.Context, q *ListQuery) (*Page[User], error) {
+	return paginate(h.db.WithContext(ctx).Model(&User{}), q, "users.id", func(user *User) uint { return user.ID })
+}
+
+func (h *UserHandler) ResetPassword(ctx context.Context, id uint, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	_, err = audited[User](h.db.WithContext(ctx), AuditPasswordReset, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).Where("id = ?", id).Update("password", string(hashedPassword))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return notFound(gorm.ErrRecordNotFound, "user")
+		}
+		return nil
+	})
+	return err
 }
 
-func (h *UserHandler) GetUsers() ([]User, error) {
-	var users []User
-	result := h.db.Find(&users)
-	return users, result.Error
+// LiftBlacklist forgives the user's current unpaid reservations so they can
+// reserve again. The reservations themselves stay unpaid.
+func (h *UserHandler) LiftBlacklist(ctx context.Context, id uint) error {
+	_, err := audited[User](h.db.WithContext(ctx), AuditBlacklistLift, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).Where("id = ?", id).Update("blacklist_lifted_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return notFound(gorm.ErrRecordNotFound, "user")
+		}
+		return nil
+	})
+	return err
+}
+
+// UpdateUser writes user over the user id if it is still at version, and
+// sets the new ID and version on user.
+func (h *UserHandler) UpdateUser(ctx context.Context, id uint, version Version, user *User) error {
+	_, err := audited[User](h.db.WithContext(ctx), AuditUpdate, &id, func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).Where("id = ? AND version = ?", id, version).Updates(user)
+		return checkVersion(tx, result, &User{}, id, "user")
+	})
+	if err != nil {
+		return err
+	}
+	user.ID, user.Version = id, version+1
+	return nil
 }
 
-func (h *UserHandler) UpdateUser(id uint, user *User) error {
-	result := h.db.Model(&User{}).Where("id = ?", id).Updates(user)
-	return result.Error
+// PatchUser writes changes to the user id if it is still at version.
+func (h *UserHandler) PatchUser(ctx context.Context, id uint, version Version, changes map[string]interface{}) (*User, error) {
+	return patchRow[User](h.db.WithContext(ctx), id, version, changes, "user")
 }
 
-func (h *UserHandler) DeleteUser(id uint) error {
-	result := h.db.Delete(&User{}, id)
-	return result.Error
+func (h *UserHandler) DeleteUser(ctx context.Context, id uint, version Version) error {
+	_, err := audited[User](h.db.WithContext(ctx), AuditDelete, &id, func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", version).Delete(&User{}, id)
+		return checkVersion(tx, result, &User{}, id, "user")
+	})
+	return err
 }
 
-func (h *UserHandler) GetUserByEmail(email string) (*User, error) {
+func (h *UserHandler) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
-	result := h.db.Where("email = ?", email).First(&user)
+	result := h.db.WithContext(ctx).Where("email = ?", email).First(&user)
 	if result.Error != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, notFound(result.Error, "user")
 	}
-	return &user, result.Error
+	return &user, nil
 }
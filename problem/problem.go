@@ -0,0 +1,154 @@
+// Package problem writes error responses as RFC 7807 problem details.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// CodeKey is the context key the code of the problem a request was answered
+// with is stored under, for the request log.
+const CodeKey = "error_code"
+
+// Details is an RFC 7807 problem details object. Code is a stable, machine
+// readable identifier of the error that clients can switch on, and Errors
+// lists the rejected fields of a validation error.
+type Details struct {
+	Type     string              `json:"type" example:"about:blank"`
+	Title    string              `json:"title" example:"Not Found"`
+	Status   int                 `json:"status" example:"404"`
+	Detail   string              `json:"detail,omitempty" example:"food not found"`
+	Instance string              `json:"instance,omitempty" example:"/api/v1/food/42"`
+	Code     string              `json:"code" example:"not_found"`
+	Errors   []models.FieldError `json:"errors,omitempty"`
+}
+
+// New returns the problem details of status.
+func New(status int, code, detail string) *Details {
+	return &Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Write aborts the request with p.
+func Write(c *gin.Context, p *Details) {
+	if p.Instance == "" && c.Request != nil {
+		p.Instance = c.Request.URL.Path
+	}
+	c.Set(CodeKey, p.Code)
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Respond aborts the request with the problem of status.
+func Respond(c *gin.Context, status int, code, detail string) {
+	Write(c, New(status, code, detail))
+}
+
+// Status returns the status code of a domain error kind.
+func Status(kind models.Kind) int {
+	switch kind {
+	case models.KindNotFound:
+		return http.StatusNotFound
+	case models.KindConflict:
+		return http.StatusConflict
+	case models.KindForbidden, models.KindBlacklisted:
+		return http.StatusForbidden
+	case models.KindValidation:
+		return http.StatusBadRequest
+	case models.KindPrecondition:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error aborts the request with the problem for err. Domain errors keep their
+// status, code and message, anything else is logged and answered with a 500
+// whose detail is the given message, so internals are not leaked. The error
+// itself is attached to the context for the request log.
+func Error(c *gin.Context, err error, detail string) {
+	var domain *models.Error
+	if !errors.As(models.DomainError(err), &domain) || domain.Kind == models.KindInternal {
+		c.Error(err)
+		Respond(c, http.StatusInternalServerError, "internal_error", detail)
+		return
+	}
+
+	p := New(Status(domain.Kind), domain.Code, models.DomainError(err).Error())
+	p.Errors = domain.Fields
+	Write(c, p)
+}
+
+// Bind aborts the request with the problem for an error returned while
+// binding its body. Failed validations list every rejected field.
+func Bind(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		Respond(c, http.StatusBadRequest, "invalid_body", bindDetail(err))
+		return
+	}
+
+	Error(c, models.ValidationError(fieldErrors(invalid)...), "")
+}
+
+func fieldErrors(invalid validator.ValidationErrors) []models.FieldError {
+	fields := make([]models.FieldError, 0, len(invalid))
+	for _, field := range invalid {
+		fields = append(fields, models.FieldError{Field: field.Field(), Message: fieldMessage(field)})
+	}
+	return fields
+}
+
+func bindDetail(err error) string {
+	var syntax *json.SyntaxError
+	var unmarshal *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return "request body is empty"
+	case errors.As(err, &syntax), errors.Is(err, io.ErrUnexpectedEOF):
+		return "request body is not valid JSON"
+	case errors.As(err, &unmarshal):
+		return fmt.Sprintf("%s must be a %s", unmarshal.Field, unmarshal.Type)
+	default:
+		return err.Error()
+	}
+}
+
+// fieldMessage describes a failed validation tag.
+func fieldMessage(field validator.FieldError) string {
+	switch field.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + field.Param()
+	case "max":
+		return "must be at most " + field.Param()
+	case "oneof":
+		return "must be one of " + field.Param()
+	case "phone":
+		return "must be a valid phone number"
+	case "notpast":
+		return "must not be in the past"
+	case "exists":
+		return "must reference an existing " + field.Param()
+	default:
+		return "failed the " + field.Tag() + " check"
+	}
+}
@@ -1,27 +1,34 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/Hamedblue1381/restaurant-reserve/metrics"
 	"github.com/Hamedblue1381/restaurant-reserve/middleware"
 	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
-
-	"gorm.io/gorm"
 )
 
-var userHandler *models.UserHandler
+// AuthHandler handles registration and sign in.
+type AuthHandler struct {
+	users    models.UserStore
+	tokens   *middleware.JWT
+	metrics  *metrics.Metrics
+	validate *validation.Validator
+}
 
-func InitializedAuthHandler(db *gorm.DB) {
-	userHandler = models.NewUserHandler(db)
+func NewAuthHandler(users models.UserStore, tokens *middleware.JWT, metrics *metrics.Metrics, validate *validation.Validator) *AuthHandler {
+	return &AuthHandler{users, tokens, metrics, validate}
 }
 
 type RegisterDetails struct {
-	Name      string `json:"name" example:"John Doe"`
-	Telephone string `json:"telephone" example:"123-456-7890"`
-	Email     string `json:"email" example:"john.doe@example.com"`
-	Password  string `json:"password" example:"securePassword123"`
-	Role      string `json:"role" example:"user"`
+	Name      string `json:"name" binding:"required,max=100" example:"John Doe"`
+	Telephone string `json:"telephone" binding:"omitempty,phone" example:"123-456-7890"`
+	Email     string `json:"email" binding:"required,email" example:"john.doe@example.com"`
+	Password  string `json:"password" binding:"required,min=8,max=72" example:"securePassword123"`
 }
 
 type RegisterResponse struct {
@@ -35,27 +42,36 @@ type RegisterResponse struct {
 // @Produce json
 // @Param user body RegisterDetails true "Register Credentials"
 // @Success 200 {object} RegisterResponse "Confirmation of successful registration."
-// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
-// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Failure 400 {object} problem.Details "The request was formatted incorrectly or missing required fields."
+// @Failure 500 {object} problem.Details "Internal server error, unable to process the request."
 // @Router /auth/register [post]
-func Register(c *gin.Context) {
+func (h *AuthHandler) Register(c *gin.Context) {
 
-	var newUser models.User
+	var details RegisterDetails
 
-	if err := c.ShouldBindJSON(&newUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+	if err := h.validate.Bind(c, &details); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	err := userHandler.CreateUser(&newUser)
+	// Admins are created with the create-admin command or by another admin
+	newUser := models.User{
+		Name:      details.Name,
+		Telephone: details.Telephone,
+		Email:     details.Email,
+		Password:  details.Password,
+		Role:      "user",
+	}
+
+	err := h.users.CreateUser(c.Request.Context(), &newUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating user"})
+		problem.Error(c, err, "Error creating user")
 		return
 	}
 
-	token, err := middleware.GenerateToken(newUser.Email, newUser.ID, newUser.Role)
+	token, err := h.tokens.GenerateToken(newUser.Email, newUser.ID, newUser.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		problem.Error(c, err, "Error generating token")
 		return
 	}
 
@@ -70,8 +86,8 @@ func Register(c *gin.Context) {
 
 type LoginDetails struct {
 	// Telephone string `json:"telephone" example:"09211212121"`
-	Email    string `json:"email" example:"user@example.com"`
-	Password string `json:"password" example:"password123"`
+	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
+	Password string `json:"password" binding:"required" example:"password123"`
 }
 
 type LoginResponse struct {
@@ -79,10 +95,6 @@ type LoginResponse struct {
 	Message string `json:"message" example:"Login successful"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error" example:"Error message"`
-}
-
 // Login a user
 // @Summary User Login
 // @Description Authenticates a user by their email and password, returning a JWT token for authorized access to protected endpoints if successful.
@@ -91,39 +103,39 @@ type ErrorResponse struct {
 // @Produce json
 // @Param credentials body LoginDetails true "Login Credentials"
 // @Success 200 {object} LoginResponse "An object containing a JWT token for authentication and a message indicating successful login."
-// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
-// @Failure 401 {object} ErrorResponse "Authentication failed due to invalid login credentials."
-// @Failure 404 {object} ErrorResponse "The specified user was not found in the system."
-// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Failure 400 {object} problem.Details "The request was formatted incorrectly or missing required fields."
+// @Failure 401 {object} problem.Details "Authentication failed due to invalid login credentials."
+// @Failure 500 {object} problem.Details "Internal server error, unable to process the request."
 // @Router /auth/signin [post]
-func Login(c *gin.Context) {
+func (h *AuthHandler) Login(c *gin.Context) {
 
 	var loginDetails LoginDetails
-	if err := c.ShouldBindJSON(&loginDetails); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+	if err := h.validate.Bind(c, &loginDetails); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	user, err := userHandler.GetUserByEmail(loginDetails.Email)
+	user, err := h.users.GetUserByEmail(c.Request.Context(), loginDetails.Email)
 
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	if errors.Is(err, models.ErrNotFound) {
+		h.metrics.LoginFailures.WithLabelValues("unknown_email").Inc()
+		problem.Respond(c, http.StatusUnauthorized, "invalid_credentials", "Email or password is incorrect")
 		return
 	}
-
-	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Authentication failed"})
+	if err != nil {
+		problem.Error(c, err, "Error signing in")
 		return
 	}
 
-	if !userHandler.CheckPassword(user.Email, loginDetails.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect!"})
+	if !h.users.CheckPassword(c.Request.Context(), user.Email, loginDetails.Password) {
+		h.metrics.LoginFailures.WithLabelValues("wrong_password").Inc()
+		problem.Respond(c, http.StatusUnauthorized, "invalid_credentials", "Email or password is incorrect")
 		return
 	}
 
-	token, err := middleware.GenerateToken(user.Email, user.ID, user.Role)
+	token, err := h.tokens.GenerateToken(user.Email, user.ID, user.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		problem.Error(c, err, "Error generating token")
 		return
 	}
 
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// etag formats the version of a resource as an entity tag.
+func etag(version models.Version) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// respondVersioned writes obj with the ETag of its version, or 304 Not
+// Modified when the If-None-Match header already names that version.
+func respondVersioned(c *gin.Context, status int, version models.Version, obj interface{}) {
+	tag := etag(version)
+	c.Header("ETag", tag)
+
+	if header := c.GetHeader("If-None-Match"); header != "" && status == http.StatusOK {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == tag || candidate == "*" {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
+	c.JSON(status, obj)
+}
+
+// ifMatch returns the version named by the If-Match header, which every
+// update and delete must send so it cannot overwrite a change it has not
+// seen. It writes the error response itself and reports whether a version
+// was given.
+func ifMatch(c *gin.Context) (models.Version, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		problem.Respond(c, http.StatusPreconditionRequired, "precondition_required", "If-Match header with the ETag of the resource is required")
+		return 0, false
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(header, `"`), 10, 64)
+	if err != nil || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		problem.Respond(c, http.StatusPreconditionFailed, "version_mismatch", "If-Match does not match the current ETag")
+		return 0, false
+	}
+	return models.Version(version), true
+}
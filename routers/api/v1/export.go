@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/export"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler streams spreadsheets of reservations, users and foods.
+type ExportHandler struct {
+	reservations models.ReservationStore
+	users        models.UserStore
+	foods        models.FoodStore
+}
+
+func NewExportHandler(reservations models.ReservationStore, users models.UserStore, foods models.FoodStore) *ExportHandler {
+	return &ExportHandler{reservations, users, foods}
+}
+
+// startExport validates the requested format and prepares a streaming
+// response named after name. It writes the error response itself on failure.
+func startExport(c *gin.Context, name string) (export.Writer, bool) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		problem.Respond(c, http.StatusBadRequest, "invalid_query", "Invalid format, expected csv or xlsx")
+		return nil, false
+	}
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	c.Status(http.StatusOK)
+
+	w, err := export.NewWriter(c.Writer, format)
+	if err != nil {
+		problem.Error(c, err, "Failed to start export")
+		return nil, false
+	}
+	return w, true
+}
+
+// finishExport closes the export. Once rows are streamed the status code is
+// already sent, so a failure can only be logged and the download is truncated.
+func finishExport(c *gin.Context, w export.Writer, err error) {
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "export failed", "error", err)
+		c.Error(err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		slog.ErrorContext(c.Request.Context(), "export failed", "error", err)
+		c.Error(err)
+	}
+}
+
+func formatUint(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// @Summary Export reservations
+// @Description Streams reservations as a CSV or Excel file. Accepts the same filters and sort keys as listing reservations; pagination parameters are ignored.
+// @Tags export
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param start_date query string false "Start date (format: yyyy-mm-dd)"
+// @Param end_date query string false "End date (format: yyyy-mm-dd)"
+// @Param format query string false "Output format" Enums(csv, xlsx) default(csv)
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, date, status)
+// @Param user_id query int false "Filter by user"
+// @Param food_id query int false "Filter by food"
+// @Param side_id query int false "Filter by side"
+// @Param status query string false "Filter by status" Enums(reserved, served, cancelled)
+// @Param is_paid query bool false "Filter by payment state"
+// @Security Bearer
+// @Success 200 {file} file "The exported reservations"
+// @Failure 400 {object} problem.Details "Invalid date, filter or output format"
+// @Router /export/reservations [get]
+func (h *ExportHandler) ExportReservations(c *gin.Context) {
+	startDate, endDate, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	q, ok := parseListQuery(c, models.ReservationListSpec)
+	if !ok {
+		return
+	}
+
+	w, ok := startExport(c, "reservations")
+	if !ok {
+		return
+	}
+
+	err := w.WriteRow([]string{"id", "date", "status", "is_paid", "user_id", "user_name", "user_email", "food_id", "food_name", "side_id", "side_name"})
+	if err == nil {
+		err = h.reservations.ExportReservations(c.Request.Context(), startDate, endDate, q, func(r *models.ReservationExport) error {
+			return w.WriteRow([]string{
+				formatUint(r.ID),
+				r.Date.Format("2006-01-02"),
+				r.Status,
+				strconv.FormatBool(r.IsPaid),
+				formatUint(r.UserID),
+				r.UserName,
+				r.UserEmail,
+				formatUint(r.FoodID),
+				r.FoodName,
+				formatUint(r.SideID),
+				r.SideName,
+			})
+		})
+	}
+
+	finishExport(c, w, err)
+}
+
+// @Summary Export users
+// @Description Streams all users as a CSV or Excel file. Passwords are never exported.
+// @Tags export
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Output format" Enums(csv, xlsx) default(csv)
+// @Security Bearer
+// @Success 200 {file} file "The exported users"
+// @Failure 400 {object} problem.Details "Invalid output format"
+// @Router /export/users [get]
+func (h *ExportHandler) ExportUsers(c *gin.Context) {
+	w, ok := startExport(c, "users")
+	if !ok {
+		return
+	}
+
+	err := w.WriteRow([]string{"id", "name", "email", "telephone", "role", "created_at"})
+	if err == nil {
+		err = h.users.ExportUsers(c.Request.Context(), func(u *models.User) error {
+			return w.WriteRow([]string{
+				formatUint(u.ID),
+				u.Name,
+				u.Email,
+				u.Telephone,
+				u.Role,
+				u.CreatedAt.Format(time.RFC3339),
+			})
+		})
+	}
+
+	finishExport(c, w, err)
+}
+
+// @Summary Export foods
+// @Description Streams all foods with their category, meal type and rating as a CSV or Excel file.
+// @Tags export
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Output format" Enums(csv, xlsx) default(csv)
+// @Security Bearer
+// @Success 200 {file} file "The exported foods"
+// @Failure 400 {object} problem.Details "Invalid output format"
+// @Router /export/foods [get]
+func (h *ExportHandler) ExportFoods(c *gin.Context) {
+	w, ok := startExport(c, "foods")
+	if !ok {
+		return
+	}
+
+	err := w.WriteRow([]string{"id", "name", "quantity", "category", "meal_type", "rating_count", "rating_average"})
+	if err == nil {
+		err = h.foods.ExportFoods(c.Request.Context(), func(f *models.FoodExport) error {
+			return w.WriteRow([]string{
+				formatUint(f.ID),
+				f.Name,
+				f.Quanity,
+				f.CategoryName,
+				f.MealTypeName,
+				strconv.Itoa(f.RatingCount),
+				strconv.FormatFloat(f.RatingAverage, 'f', 2, 64),
+			})
+		})
+	}
+
+	finishExport(c, w, err)
+}
@@ -5,14 +5,19 @@ import (
 	"strconv"
 
 	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-var foodHandler *models.FoodHandler
+// FoodHandler handles the food endpoints.
+type FoodHandler struct {
+	foods    models.FoodStore
+	validate *validation.Validator
+}
 
-func InitializedFoodHandler(db *gorm.DB) {
-	foodHandler = models.NewFoodHandler(db)
+func NewFoodHandler(foods models.FoodStore, validate *validation.Validator) *FoodHandler {
+	return &FoodHandler{foods, validate}
 }
 
 // @Summary Get a Single food Dish
@@ -20,44 +25,60 @@ func InitializedFoodHandler(db *gorm.DB) {
 // @Tags food
 // @Produce json
 // @Param id path int true "food ID" Format(int64)
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.Food "The details of the food including ID, name, quantity, category, mealtype."
-// @Failure 400 {object} ErrorResponse "Invalid food ID format."
-// @Failure 404 {object} ErrorResponse "Food not found with the specified ID."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Success 304 "Not modified, If-None-Match names the current ETag."
+// @Failure 400 {object} problem.Details "Invalid food ID format."
+// @Failure 404 {object} problem.Details "Food not found with the specified ID."
 // @Router /food/{id} [get]
-func GetFood(c *gin.Context) {
+func (h *FoodHandler) GetFood(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid food id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid food id")
 		return
 	}
 
 	idUint := uint(idInt)
 
-	user, err := foodHandler.GetFood(idUint)
+	user, err := h.foods.GetFood(c.Request.Context(), idUint)
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Food not found"})
+		problem.Error(c, err, "Error fetching food")
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	respondVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // @Summary Get All Foods
-// @Description Retrieves a list of all foods in the system.
+// @Description Retrieves a page of foods in the system.
 // @Tags food
 // @Produce json
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, name, rating_average, rating_count)
+// @Param name query string false "Filter by name"
+// @Param category_id query int false "Filter by category"
+// @Param meal_type_id query int false "Filter by meal type"
 // @Security Bearer
-// @Success 200 {array} models.Food "An array of food objects."
-// @Failure 500 {object} ErrorResponse "Internal server error while fetching foods."
+// @Success 200 {object} models.Page[models.Food] "A page of food objects."
+// @Failure 400 {object} problem.Details "Invalid pagination, sort or filter parameters."
+// @Failure 500 {object} problem.Details "Internal server error while fetching foods."
 // @Router /food [get]
-func GetFoods(c *gin.Context) {
-	foods, err := foodHandler.GetFoods()
+func (h *FoodHandler) GetFoods(c *gin.Context) {
+	q, ok := parseListQuery(c, models.FoodListSpec)
+	if !ok {
+		return
+	}
+
+	foods, err := h.foods.GetFoods(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching foods!"})
+		problem.Error(c, err, "Error fetching foods!")
 		return
 	}
 
@@ -72,23 +93,23 @@ func GetFoods(c *gin.Context) {
 // @Param food body models.Food true "Food Details"
 // @Security Bearer
 // @Success 201 {object} models.Food "The created Food's details, including their unique identifier."
-// @Failure 400 {object} ErrorResponse "Invalid input format for Food."
-// @Failure 500 {object} ErrorResponse "Internal server error while creating the food."
+// @Failure 400 {object} problem.Details "Invalid input format for Food."
+// @Failure 500 {object} problem.Details "Internal server error while creating the food."
 // @Router /food [post]
-func CreateFood(c *gin.Context) {
+func (h *FoodHandler) CreateFood(c *gin.Context) {
 	var food models.Food
 
-	if err := c.ShouldBindJSON(&food); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &food); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	if err := foodHandler.CreateFood(&food); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating food!"})
+	if err := h.foods.CreateFood(c.Request.Context(), &food); err != nil {
+		problem.Error(c, err, "Error creating food!")
 		return
 	}
 
-	c.JSON(http.StatusCreated, food)
+	respondVersioned(c, http.StatusCreated, food.Version, food)
 }
 
 // @Summary Update a food
@@ -98,34 +119,101 @@ func CreateFood(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Food ID" Format(int64)
 // @Param food body models.Food true "Updated food Details"
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.Food "The updated food's details."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details or invalid food ID."
-// @Failure 500 {object} ErrorResponse "Internal server error while updating the food."
+// @Failure 400 {object} problem.Details "Invalid input format for user details or invalid food ID."
+// @Failure 404 {object} problem.Details "Food not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while updating the food."
 // @Router /food/{id} [put]
-func UpdateFood(c *gin.Context) {
+func (h *FoodHandler) UpdateFood(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid food id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid food id")
 		return
 	}
 	idUint := uint(idInt)
 
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
 	var food models.Food
 
-	if err := c.ShouldBindJSON(&food); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &food); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	err = h.foods.UpdateFood(c.Request.Context(), idUint, version, &food)
+	if err != nil {
+		problem.Error(c, err, "Error updating food")
+		return
+	}
+
+	respondVersioned(c, http.StatusOK, food.Version, food)
+}
+
+// @Summary Patch a food
+// @Description Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name, quanity, description, CategoryID, MealTypeID.
+// @Tags food
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Food ID" Format(int64)
+// @Param patch body object true "Fields to change"
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 200 {object} models.Food "The patched food."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Failure 400 {object} problem.Details "Invalid food ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date."
+// @Failure 403 {object} problem.Details "The patch changes fields only admins may change."
+// @Failure 404 {object} problem.Details "Food not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 415 {object} problem.Details "Unsupported patch format."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while patching the food."
+// @Router /food/{id} [patch]
+func (h *FoodHandler) PatchFood(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid food id")
+		return
+	}
+	idUint := uint(idInt)
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, ok := parsePatch(c, models.FoodPatchSpec)
+	if !ok {
+		return
+	}
+
+	current, err := h.foods.GetFood(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error patching food")
+		return
+	}
+	if !validPatch(c, h.validate, current, changes) {
 		return
 	}
 
-	err = foodHandler.UpdateFood(idUint, &food)
+	food, err := h.foods.PatchFood(c.Request.Context(), idUint, version, changes)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating food"})
+		problem.Error(c, err, "Error patching food")
 		return
 	}
 
-	c.JSON(http.StatusOK, food)
+	respondVersioned(c, http.StatusOK, food.Version, food)
 }
 
 // @Summary Delete a food
@@ -133,23 +221,32 @@ func UpdateFood(c *gin.Context) {
 // @Tags food
 // @Produce json
 // @Param id path int true "food ID" Format(int64)
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 204 "Food successfully deleted, no content to return."
-// @Failure 400 {object} ErrorResponse "Invalid food ID format."
-// @Failure 500 {object} ErrorResponse "Internal server error while deleting the food."
+// @Failure 400 {object} problem.Details "Invalid food ID format."
+// @Failure 404 {object} problem.Details "Food not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while deleting the food."
 // @Router /food/{id} [delete]
-func DeleteFood(c *gin.Context) {
+func (h *FoodHandler) DeleteFood(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid food id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid food id")
 		return
 	}
 	idUint := uint(idInt)
 
-	err = foodHandler.DeleteFood(idUint)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.foods.DeleteFood(c.Request.Context(), idUint, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting food"})
+		problem.Error(c, err, "Error deleting food")
 		return
 	}
 
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportHandler handles the import endpoints.
+type ImportHandler struct {
+	imports models.ImportStore
+}
+
+func NewImportHandler(imports models.ImportStore) *ImportHandler {
+	return &ImportHandler{imports}
+}
+
+// @Summary Import records from CSV
+// @Description Bulk imports users (name, email, telephone, role, password), foods (name, quantity, category, meal_type), sides (name, quantity) or menus (meal_type, category, food, quantity) from a CSV file with a header line.
+// @Description Rows are upserted by email for users and by name otherwise. The whole file is imported in one transaction and nothing is written if any row is invalid or dry_run is set.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param resource path string true "Resource to import" Enums(users, foods, sides, menus)
+// @Param file formData file true "CSV file"
+// @Param dry_run query bool false "Validate the file without writing anything"
+// @Security Bearer
+// @Success 200 {object} models.ImportReport "The import report."
+// @Failure 400 {object} problem.Details "Unknown resource, missing file or malformed CSV."
+// @Failure 422 {object} models.ImportReport "The file contains invalid rows, nothing was imported."
+// @Failure 500 {object} problem.Details "Internal server error while importing."
+// @Router /import/{resource} [post]
+func (h *ImportHandler) ImportCSV(c *gin.Context) {
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			problem.Respond(c, http.StatusBadRequest, "invalid_query", "Invalid dry_run flag")
+			return
+		}
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_file", "Missing CSV file")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_file", "Unable to read CSV file")
+		return
+	}
+	defer file.Close()
+
+	report, err := h.imports.Import(c.Request.Context(), c.Param("resource"), file, dryRun)
+	if err != nil {
+		problem.Error(c, err, "Error importing file")
+		return
+	}
+
+	if len(report.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// parseListQuery parses the pagination, sort and filter parameters allowed by
+// spec. It writes the error response itself and reports whether parsing
+// succeeded.
+func parseListQuery(c *gin.Context, spec models.ListSpec) (*models.ListQuery, bool) {
+	q, err := spec.Parse(c.Request.URL.Query())
+	if err != nil {
+		problem.Error(c, err, "Invalid query")
+		return nil, false
+	}
+	return q, true
+}
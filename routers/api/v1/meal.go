@@ -5,14 +5,19 @@ import (
 	"strconv"
 
 	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-var mealtypeHandler *models.MealTypeHandler
+// MealTypeHandler handles the meal type endpoints.
+type MealTypeHandler struct {
+	mealTypes models.MealTypeStore
+	validate  *validation.Validator
+}
 
-func InitializedMealTypeHandler(db *gorm.DB) {
-	mealtypeHandler = models.NewMealTypeHandler(db)
+func NewMealTypeHandler(mealTypes models.MealTypeStore, validate *validation.Validator) *MealTypeHandler {
+	return &MealTypeHandler{mealTypes, validate}
 }
 
 // @Summary Get a Single mealtype Dish
@@ -20,44 +25,58 @@ func InitializedMealTypeHandler(db *gorm.DB) {
 // @Tags mealtype
 // @Produce json
 // @Param id path int true "mealtype ID" Format(int64)
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.MealType "The details of the mealtype including ID, name, quantity, category, mealtype."
-// @Failure 400 {object} ErrorResponse "Invalid mealtype ID format."
-// @Failure 404 {object} ErrorResponse "MealType not found with the specified ID."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Success 304 "Not modified, If-None-Match names the current ETag."
+// @Failure 400 {object} problem.Details "Invalid mealtype ID format."
+// @Failure 404 {object} problem.Details "MealType not found with the specified ID."
 // @Router /mealtype/{id} [get]
-func GetMealType(c *gin.Context) {
+func (h *MealTypeHandler) GetMealType(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mealtype id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid mealtype id")
 		return
 	}
 
 	idUint := uint(idInt)
 
-	user, err := mealtypeHandler.GetMealType(idUint)
+	user, err := h.mealTypes.GetMealType(c.Request.Context(), idUint)
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "MealType not found"})
+		problem.Error(c, err, "Error fetching mealtype")
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	respondVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // @Summary Get All MealTypes
-// @Description Retrieves a list of all mealtypes in the system.
+// @Description Retrieves a page of mealtypes in the system.
 // @Tags mealtype
 // @Produce json
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, name)
+// @Param name query string false "Filter by name"
 // @Security Bearer
-// @Success 200 {array} models.MealType "An array of mealtype objects."
-// @Failure 500 {object} ErrorResponse "Internal server error while fetching mealtypes."
+// @Success 200 {object} models.Page[models.MealType] "A page of mealtype objects."
+// @Failure 400 {object} problem.Details "Invalid pagination, sort or filter parameters."
+// @Failure 500 {object} problem.Details "Internal server error while fetching mealtypes."
 // @Router /mealtypes [get]
-func GetMealTypes(c *gin.Context) {
-	mealtypes, err := mealtypeHandler.GetMealTypes()
+func (h *MealTypeHandler) GetMealTypes(c *gin.Context) {
+	q, ok := parseListQuery(c, models.MealTypeListSpec)
+	if !ok {
+		return
+	}
+
+	mealtypes, err := h.mealTypes.GetMealTypes(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching mealtypes!"})
+		problem.Error(c, err, "Error fetching mealtypes!")
 		return
 	}
 
@@ -72,23 +91,23 @@ func GetMealTypes(c *gin.Context) {
 // @Param mealtype body models.MealType true "MealType Details"
 // @Security Bearer
 // @Success 201 {object} models.MealType "The created MealType's details, including their unique identifier."
-// @Failure 400 {object} ErrorResponse "Invalid input format for MealType."
-// @Failure 500 {object} ErrorResponse "Internal server error while creating the mealtype."
+// @Failure 400 {object} problem.Details "Invalid input format for MealType."
+// @Failure 500 {object} problem.Details "Internal server error while creating the mealtype."
 // @Router /mealtype [post]
-func CreateMealType(c *gin.Context) {
+func (h *MealTypeHandler) CreateMealType(c *gin.Context) {
 	var mealtype models.MealType
 
-	if err := c.ShouldBindJSON(&mealtype); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &mealtype); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	if err := mealtypeHandler.CreateMealType(&mealtype); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating mealtype!"})
+	if err := h.mealTypes.CreateMealType(c.Request.Context(), &mealtype); err != nil {
+		problem.Error(c, err, "Error creating mealtype!")
 		return
 	}
 
-	c.JSON(http.StatusCreated, mealtype)
+	respondVersioned(c, http.StatusCreated, mealtype.Version, mealtype)
 }
 
 // @Summary Update a mealtype
@@ -98,34 +117,101 @@ func CreateMealType(c *gin.Context) {
 // @Produce json
 // @Param id path int true "MealType ID" Format(int64)
 // @Param mealtype body models.MealType true "Updated mealtype Details"
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.MealType "The updated mealtype's details."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details or invalid mealtype ID."
-// @Failure 500 {object} ErrorResponse "Internal server error while updating the mealtype."
+// @Failure 400 {object} problem.Details "Invalid input format for user details or invalid mealtype ID."
+// @Failure 404 {object} problem.Details "Meal type not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while updating the mealtype."
 // @Router /mealtype/{id} [put]
-func UpdateMealType(c *gin.Context) {
+func (h *MealTypeHandler) UpdateMealType(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mealtype id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid mealtype id")
 		return
 	}
 	idUint := uint(idInt)
 
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
 	var mealtype models.MealType
 
-	if err := c.ShouldBindJSON(&mealtype); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &mealtype); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	err = mealtypeHandler.UpdateMealType(idUint, &mealtype)
+	err = h.mealTypes.UpdateMealType(c.Request.Context(), idUint, version, &mealtype)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating mealtype"})
+		problem.Error(c, err, "Error updating mealtype")
 		return
 	}
 
-	c.JSON(http.StatusOK, mealtype)
+	respondVersioned(c, http.StatusOK, mealtype.Version, mealtype)
+}
+
+// @Summary Patch a meal type
+// @Description Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name.
+// @Tags mealtype
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Meal type ID" Format(int64)
+// @Param patch body object true "Fields to change"
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 200 {object} models.MealType "The patched meal type."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Failure 400 {object} problem.Details "Invalid meal type ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date."
+// @Failure 403 {object} problem.Details "The patch changes fields only admins may change."
+// @Failure 404 {object} problem.Details "Meal type not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 415 {object} problem.Details "Unsupported patch format."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while patching the meal type."
+// @Router /mealtype/{id} [patch]
+func (h *MealTypeHandler) PatchMealType(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid meal type id")
+		return
+	}
+	idUint := uint(idInt)
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, ok := parsePatch(c, models.MealTypePatchSpec)
+	if !ok {
+		return
+	}
+
+	current, err := h.mealTypes.GetMealType(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error patching meal type")
+		return
+	}
+	if !validPatch(c, h.validate, current, changes) {
+		return
+	}
+
+	mealType, err := h.mealTypes.PatchMealType(c.Request.Context(), idUint, version, changes)
+	if err != nil {
+		problem.Error(c, err, "Error patching meal type")
+		return
+	}
+
+	respondVersioned(c, http.StatusOK, mealType.Version, mealType)
 }
 
 // @Summary Delete a mealtype
@@ -133,23 +219,32 @@ func UpdateMealType(c *gin.Context) {
 // @Tags mealtype
 // @Produce json
 // @Param id path int true "mealtype ID" Format(int64)
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 204 "MealType successfully deleted, no content to return."
-// @Failure 400 {object} ErrorResponse "Invalid mealtype ID format."
-// @Failure 500 {object} ErrorResponse "Internal server error while deleting the mealtype."
+// @Failure 400 {object} problem.Details "Invalid mealtype ID format."
+// @Failure 404 {object} problem.Details "Meal type not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while deleting the mealtype."
 // @Router /mealtype/{id} [delete]
-func DeleteMealType(c *gin.Context) {
+func (h *MealTypeHandler) DeleteMealType(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mealtype id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid mealtype id")
 		return
 	}
 	idUint := uint(idInt)
 
-	err = mealtypeHandler.DeleteMealType(idUint)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.mealTypes.DeleteMealType(c.Request.Context(), idUint, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting mealtype"})
+		problem.Error(c, err, "Error deleting mealtype")
 		return
 	}
 
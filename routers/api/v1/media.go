@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hamedblue1381/restaurant-reserve/media"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxImageSize  = 5 << 20
+	thumbnailSize = 256
+)
+
+// MediaHandler handles image uploads and serves stored media.
+type MediaHandler struct {
+	foods models.FoodStore
+	sides models.SidesStore
+	blobs storage.BlobStore
+}
+
+func NewMediaHandler(foods models.FoodStore, sides models.SidesStore, blobs storage.BlobStore) *MediaHandler {
+	return &MediaHandler{foods, sides, blobs}
+}
+
+type ImageResponse struct {
+	ImageKey     string `json:"image_key" example:"food/1/3f2a9c1b7d4e8f60.png"`
+	ThumbnailKey string `json:"thumbnail_key" example:"food/1/3f2a9c1b7d4e8f60_thumb.jpg"`
+}
+
+// storeImage reads the "image" form file, validates it and stores both the
+// original and a thumbnail under prefix. The keys are derived from the content
+// hash so a stored blob never changes and can be cached forever.
+func (h *MediaHandler) storeImage(c *gin.Context, prefix string) (*ImageResponse, bool) {
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_file", "Missing image file")
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_file", "Unable to read image file")
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_file", "Unable to read image file")
+		return nil, false
+	}
+	if len(data) > maxImageSize {
+		problem.Respond(c, http.StatusRequestEntityTooLarge, "payload_too_large", "Image must not be larger than 5MB")
+		return nil, false
+	}
+
+	_, ext, err := media.Sniff(data)
+	if err != nil {
+		problem.Respond(c, http.StatusUnsupportedMediaType, "unsupported_media_type", err.Error())
+		return nil, false
+	}
+
+	thumbnail, err := media.Thumbnail(data, thumbnailSize)
+	if errors.Is(err, media.ErrImageTooLarge) {
+		problem.Respond(c, http.StatusRequestEntityTooLarge, "payload_too_large", err.Error())
+		return nil, false
+	}
+	if err != nil {
+		problem.Respond(c, http.StatusUnsupportedMediaType, "unsupported_media_type", err.Error())
+		return nil, false
+	}
+
+	sum := sha256.Sum256(data)
+	name := fmt.Sprintf("%s/%x", prefix, sum[:8])
+	keys := &ImageResponse{
+		ImageKey:     name + ext,
+		ThumbnailKey: name + "_thumb.jpg",
+	}
+
+	if err := h.blobs.Put(keys.ImageKey, bytes.NewReader(data)); err != nil {
+		problem.Error(c, err, "Error storing image")
+		return nil, false
+	}
+	if err := h.blobs.Put(keys.ThumbnailKey, bytes.NewReader(thumbnail)); err != nil {
+		problem.Error(c, err, "Error storing image")
+		return nil, false
+	}
+
+	return keys, true
+}
+
+// removeImage deletes the blobs of a replaced image, unless the new upload
+// produced the very same keys.
+func (h *MediaHandler) removeImage(oldKeys, newKeys *ImageResponse) {
+	if oldKeys.ImageKey != "" && oldKeys.ImageKey != newKeys.ImageKey {
+		h.blobs.Delete(oldKeys.ImageKey)
+	}
+	if oldKeys.ThumbnailKey != "" && oldKeys.ThumbnailKey != newKeys.ThumbnailKey {
+		h.blobs.Delete(oldKeys.ThumbnailKey)
+	}
+}
+
+// @Summary Upload a food image
+// @Description Uploads a JPEG, PNG or GIF image for a food dish and generates a thumbnail. The content type is detected from the file content.
+// @Tags food
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Food ID" Format(int64)
+// @Param image formData file true "Image file (max 5MB)"
+// @Security Bearer
+// @Success 200 {object} ImageResponse "The keys of the stored image and thumbnail."
+// @Failure 400 {object} problem.Details "Invalid food ID or missing image file."
+// @Failure 404 {object} problem.Details "Food not found with the specified ID."
+// @Failure 413 {object} problem.Details "Image is larger than 5MB or 25 megapixels."
+// @Failure 415 {object} problem.Details "File is not a supported image."
+// @Failure 500 {object} problem.Details "Internal server error while storing the image."
+// @Router /food/{id}/image [post]
+func (h *MediaHandler) UploadFoodImage(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid food id")
+		return
+	}
+	idUint := uint(idInt)
+
+	food, err := h.foods.GetFood(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error fetching food")
+		return
+	}
+
+	keys, ok := h.storeImage(c, "food/"+idString)
+	if !ok {
+		return
+	}
+
+	if err := h.foods.SetFoodImage(c.Request.Context(), idUint, keys.ImageKey, keys.ThumbnailKey); err != nil {
+		problem.Error(c, err, "Error updating food")
+		return
+	}
+	h.removeImage(&ImageResponse{food.ImageKey, food.ThumbnailKey}, keys)
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Upload a side dish image
+// @Description Uploads a JPEG, PNG or GIF image for a side dish and generates a thumbnail. The content type is detected from the file content.
+// @Tags sides
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Sides ID" Format(int64)
+// @Param image formData file true "Image file (max 5MB)"
+// @Security Bearer
+// @Success 200 {object} ImageResponse "The keys of the stored image and thumbnail."
+// @Failure 400 {object} problem.Details "Invalid sides ID or missing image file."
+// @Failure 404 {object} problem.Details "Sides not found with the specified ID."
+// @Failure 413 {object} problem.Details "Image is larger than 5MB or 25 megapixels."
+// @Failure 415 {object} problem.Details "File is not a supported image."
+// @Failure 500 {object} problem.Details "Internal server error while storing the image."
+// @Router /sides/{id}/image [post]
+func (h *MediaHandler) UploadSidesImage(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid sides id")
+		return
+	}
+	idUint := uint(idInt)
+
+	side, err := h.sides.GetSide(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error fetching side dish")
+		return
+	}
+
+	keys, ok := h.storeImage(c, "sides/"+idString)
+	if !ok {
+		return
+	}
+
+	if err := h.sides.SetSidesImage(c.Request.Context(), idUint, keys.ImageKey, keys.ThumbnailKey); err != nil {
+		problem.Error(c, err, "Error updating side dish")
+		return
+	}
+	h.removeImage(&ImageResponse{side.ImageKey, side.ThumbnailKey}, keys)
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Get a stored image
+// @Description Serves an uploaded image or thumbnail by its key. Keys are content addressed, so responses may be cached indefinitely.
+// @Tags media
+// @Produce image/jpeg,image/png,image/gif
+// @Param key path string true "Image key, e.g. food/1/3f2a9c1b7d4e8f60_thumb.jpg"
+// @Success 200 {file} file "The image content."
+// @Success 304 "The cached image is still valid."
+// @Failure 404 {object} problem.Details "Image not found."
+// @Router /media/{key} [get]
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	reader, info, err := h.blobs.Get(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		problem.Respond(c, http.StatusNotFound, "not_found", "Image not found")
+		return
+	}
+	if err != nil {
+		problem.Error(c, err, "Error reading image")
+		return
+	}
+	defer reader.Close()
+
+	etag := fmt.Sprintf(`"%x-%x"`, info.ModTime.Unix(), info.Size)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, reader, nil)
+}
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// parsePatch reads a JSON merge patch, or a JSON patch when the request has
+// the application/json-patch+json content type, and returns the column values
+// it writes. Admin only fields are rejected for other roles. It writes the
+// error response itself and reports whether parsing succeeded.
+func parsePatch(c *gin.Context, spec models.PatchSpec) (map[string]interface{}, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_body", "Unable to read request body")
+		return nil, false
+	}
+
+	admin := c.GetString("role") == "admin"
+	var changes map[string]interface{}
+	switch c.ContentType() {
+	case "application/merge-patch+json", "application/json", "":
+		changes, err = spec.MergePatch(body, admin)
+	case "application/json-patch+json":
+		changes, err = spec.JSONPatch(body, admin)
+	default:
+		problem.Respond(c, http.StatusUnsupportedMediaType, "unsupported_media_type", "Patch must be application/merge-patch+json or application/json-patch+json")
+		return nil, false
+	}
+
+	if err != nil {
+		problem.Error(c, err, "Unable to read patch")
+		return nil, false
+	}
+	return changes, true
+}
+
+// validPatch applies changes to current, the stored resource they patch, and
+// validates the changed fields with the same rules as a PUT or POST body, so a
+// patch cannot store what those would reject, such as a null reference or a
+// past date, and answers the same 400 validation_failed. Unchanged fields are
+// not checked again. It writes the error response itself and reports whether
+// the patched resource is valid.
+func validPatch(c *gin.Context, validate *validation.Validator, current interface{}, changes map[string]interface{}) bool {
+	fields, err := models.Apply(current, changes)
+	if err != nil {
+		problem.Error(c, err, "Unable to apply patch")
+		return false
+	}
+	if len(fields) == 0 {
+		return true
+	}
+
+	if err := validate.StructPartial(c.Request.Context(), current, fields...); err != nil {
+		problem.Bind(c, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// foods finds the food with ID 1 only.
+type foods struct{ models.FoodStore }
+
+func (foods) GetFood(ctx context.Context, id uint) (*models.Food, error) {
+	if id != 1 {
+		return nil, models.ErrNotFound
+	}
+	return &models.Food{Model: models.Model{ID: id}}, nil
+}
+
+var validate = validation.New(validation.Stores{Foods: foods{}})
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	m.Run()
+}
+
+func TestValidPatch(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tests := []struct {
+		name    string
+		current interface{}
+		changes map[string]interface{}
+		invalid string
+	}{
+		{"unchanged past date", &models.Reservation{FoodID: 1, Date: yesterday}, map[string]interface{}{"is_paid": true}, ""},
+		{"empty patch", &models.Reservation{Date: yesterday}, map[string]interface{}{}, ""},
+		{"null food", &models.Reservation{FoodID: 1}, map[string]interface{}{"food_id": uint(0)}, "FoodID"},
+		{"missing food", &models.Reservation{FoodID: 1}, map[string]interface{}{"food_id": uint(2)}, "FoodID"},
+		{"null date", &models.Reservation{FoodID: 1}, map[string]interface{}{"date": time.Time{}}, "date"},
+		{"past date", &models.Reservation{FoodID: 1}, map[string]interface{}{"date": yesterday}, "date"},
+		{"unknown role", &models.User{Name: "a", Email: "a@example.com"}, map[string]interface{}{"role": "root"}, "role"},
+		{"null email", &models.User{Name: "a", Email: "a@example.com"}, map[string]interface{}{"email": ""}, "email"},
+		{"valid name", &models.User{Name: "a", Email: "a@example.com"}, map[string]interface{}{"name": "b"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+
+			valid := validPatch(c, validate, tt.current, tt.changes)
+			if valid != (tt.invalid == "") {
+				t.Fatalf("validPatch() = %v, want %v: %s", valid, tt.invalid == "", w.Body)
+			}
+			if valid {
+				return
+			}
+
+			var p problem.Details
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != http.StatusBadRequest || p.Code != "validation_failed" || len(p.Errors) != 1 || p.Errors[0].Field != tt.invalid {
+				t.Errorf("got %d %s %+v, want 400 validation_failed rejecting %s", w.Code, p.Code, p.Errors, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestValidPatchApplies(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+
+	food := &models.Food{Name: "Rice", Quanity: "1 bowl", CategoryID: 1}
+	changes := map[string]interface{}{"quantity": "", "name": "Soup"}
+	validPatch(c, validate, food, changes)
+	if food.Name != "Soup" || food.Quanity != "" || food.CategoryID != 1 {
+		t.Errorf("patched food = %+v", food)
+	}
+}
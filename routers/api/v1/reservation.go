@@ -1,13 +1,17 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/Hamedblue1381/restaurant-reserve/metrics"
 	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/tracing"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type SuccessResponse struct {
@@ -15,10 +19,49 @@ type SuccessResponse struct {
 	Date    time.Time `json:"date"`
 }
 
-var reservationHandler *models.ReservationHandler
+// ReservationHandler handles the reservation endpoints.
+type ReservationHandler struct {
+	reservations models.ReservationStore
+	metrics      *metrics.Metrics
+	validate     *validation.Validator
+}
+
+func NewReservationHandler(reservations models.ReservationStore, metrics *metrics.Metrics, validate *validation.Validator) *ReservationHandler {
+	return &ReservationHandler{reservations, metrics, validate}
+}
 
-func InitializeReservationHandler(db *gorm.DB) {
-	reservationHandler = models.NewReservationHandler(db)
+// mealType names the meal type of a reservation in metric labels.
+func (h *ReservationHandler) mealType(c *gin.Context, id uint) string {
+	name, err := h.reservations.ReservationMealType(c.Request.Context(), id)
+	if err != nil || name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// authorize checks that the caller owns the reservation id or is an admin.
+// It writes the error response itself and reports whether the caller may
+// go on.
+func (h *ReservationHandler) authorize(c *gin.Context, id uint) bool {
+	if c.GetString("role") == "admin" {
+		return true
+	}
+	reservation, err := h.reservations.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		problem.Error(c, err, "Error fetching reservation")
+		return false
+	}
+	return owns(c, reservation)
+}
+
+// owns checks that the caller made reservation or is an admin, and writes the
+// error response otherwise.
+func owns(c *gin.Context, reservation *models.Reservation) bool {
+	if c.GetString("role") != "admin" && reservation.UserID != c.GetUint("id") {
+		problem.Respond(c, http.StatusForbidden, "forbidden", "You can only access your own reservations")
+		return false
+	}
+	return true
 }
 
 // @Summary Create a reservation
@@ -27,23 +70,26 @@ func InitializeReservationHandler(db *gorm.DB) {
 // @Accept json
 // @Produce json
 // @Param reservation body models.Reservation true "Reservation details"
+// @Param Idempotency-Key header string false "Unique key, a retry with the same key replays the first response instead of reserving again"
 // @Security Bearer
 // @Success 200 {object} SuccessResponse "The created reservation's date"
-// @Failure 403 {object} ErrorResponse "User must be logged in to update a reservation"
-// @Failure 400 {object} ErrorResponse "Invalid request format"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 403 {object} problem.Details "User must be logged in to update a reservation"
+// @Failure 400 {object} problem.Details "Invalid request format"
+// @Failure 409 {object} problem.Details "A request with the same Idempotency-Key is still in progress"
+// @Failure 422 {object} problem.Details "The Idempotency-Key was used with a different request"
+// @Failure 500 {object} problem.Details "Internal server error"
 // @Router /reservation [post]
-func CreateReservation(c *gin.Context) {
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 	var reservation models.Reservation
-	if err := c.ShouldBindJSON(&reservation); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+	if err := h.validate.Bind(c, &reservation); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
 	// Check if user is logged in
 	userId, _ := c.Get("id")
 	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to make a reservation"})
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to make a reservation")
 		return
 	}
 
@@ -51,19 +97,23 @@ func CreateReservation(c *gin.Context) {
 	userIdUint, _ := userId.(uint)
 	// Check if user has more than 3 unpaid reservations
 
-	if err := reservationHandler.IsBlackListed(userIdUint); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err})
-		c.Abort()
+	if err := h.reservations.IsBlackListed(c.Request.Context(), userIdUint); err != nil {
+		if errors.Is(err, models.ErrBlacklisted) {
+			h.metrics.BlacklistRejections.Inc()
+		}
+		problem.Error(c, err, "Error checking unpaid reservations")
 		return
 	}
 
 	// Set user ID for the reservation
 	reservation.UserID = userIdUint
 
-	if err := reservationHandler.Reserve(&reservation); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reservation"})
+	if err := h.reservations.Reserve(c.Request.Context(), &reservation); err != nil {
+		problem.Error(c, err, "Failed to create reservation")
 		return
 	}
+	tracing.SetAttributes(c.Request.Context(), tracing.ReservationID.Int64(int64(reservation.ID)))
+	h.metrics.ReservationsCreated.WithLabelValues(h.mealType(c, reservation.ID)).Inc()
 
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Reservation created successfully",
@@ -71,23 +121,88 @@ func CreateReservation(c *gin.Context) {
 	})
 }
 
-// @Summary Delete a reservation
-// @Description Delete a reservation by ID
+// @Summary Patch a reservation
+// @Description Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: FoodID, SideID, date and, for admins, UserID and is_paid.
+// @Tags reservation
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Reservation ID" Format(int64)
+// @Param patch body object true "Fields to change"
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 200 {object} models.Reservation "The patched reservation."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Failure 400 {object} problem.Details "Invalid reservation ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date."
+// @Failure 403 {object} problem.Details "The reservation belongs to another user, or the patch changes fields only admins may change."
+// @Failure 404 {object} problem.Details "Reservation not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 415 {object} problem.Details "Unsupported patch format."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while patching the reservation."
+// @Router /reservations/{id} [patch]
+func (h *ReservationHandler) PatchReservation(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid reservation id")
+		return
+	}
+	idUint := uint(idInt)
+	tracing.SetAttributes(c.Request.Context(), tracing.ReservationID.Int64(int64(idUint)))
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	current, err := h.reservations.GetReservation(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error patching reservation")
+		return
+	}
+	if !owns(c, current) {
+		return
+	}
+
+	changes, ok := parsePatch(c, models.ReservationPatchSpec)
+	if !ok {
+		return
+	}
+	if !validPatch(c, h.validate, current, changes) {
+		return
+	}
+
+	reservation, err := h.reservations.PatchReservation(c.Request.Context(), idUint, version, changes)
+	if err != nil {
+		problem.Error(c, err, "Error patching reservation")
+		return
+	}
+
+	respondVersioned(c, http.StatusOK, reservation.Version, reservation)
+}
+
+// @Summary Cancel a reservation
+// @Description Cancels a reservation by ID: its status becomes cancelled and it moves to the trash, where admins can restore it. Served reservations keep their status.
 // @Tags reservation
 // @Produce json
 // @Param id path int true "Reservation ID"
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 204 "No content"
-// @Failure 400 {object} ErrorResponse "Invalid reservation ID format"
-// @Failure 403 {object} ErrorResponse "User must be logged in to update a reservation"
-// @Failure 404 {object} ErrorResponse "Reservation not found"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 400 {object} problem.Details "Invalid reservation ID format"
+// @Failure 403 {object} problem.Details "The reservation belongs to another user"
+// @Failure 404 {object} problem.Details "Reservation not found"
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error"
 // @Router /reservation/{id} [delete]
-func DeleteReservation(c *gin.Context) {
+func (h *ReservationHandler) DeleteReservation(c *gin.Context) {
 	// Authenticate user
 	userId, _ := c.Get("id")
 	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to delete a reservation"})
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to delete a reservation")
 		return
 	}
 
@@ -95,16 +210,28 @@ func DeleteReservation(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation ID"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid reservation ID")
 		return
 	}
 	idUint := uint(idInt)
+	tracing.SetAttributes(c.Request.Context(), tracing.ReservationID.Int64(int64(idUint)))
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	if !h.authorize(c, idUint) {
+		return
+	}
 
 	// Delete reservation
-	if err := reservationHandler.DeleteReservation(idUint); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+	err = h.reservations.DeleteReservation(c.Request.Context(), idUint, version)
+	if err != nil {
+		problem.Error(c, err, "Failed to delete reservation")
 		return
 	}
+	h.metrics.ReservationsCancelled.WithLabelValues(h.mealType(c, idUint)).Inc()
 
 	c.Status(http.StatusNoContent)
 }
@@ -116,18 +243,21 @@ func DeleteReservation(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Reservation ID"
 // @Param reservation body models.Reservation true "Reservation details"
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.Reservation "The updated reservation"
-// @Failure 403 {object} ErrorResponse "User must be logged in to update a reservation"
-// @Failure 400 {object} ErrorResponse "Invalid request format"
-// @Failure 404 {object} ErrorResponse "Reservation not found"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 403 {object} problem.Details "The reservation belongs to another user"
+// @Failure 400 {object} problem.Details "Invalid request format"
+// @Failure 404 {object} problem.Details "Reservation not found"
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error"
 // @Router /reservation/{id} [put]
-func UpdateReservation(c *gin.Context) {
+func (h *ReservationHandler) UpdateReservation(c *gin.Context) {
 	// Authenticate user
 	userId, _ := c.Get("id")
 	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to update a reservation"})
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to update a reservation")
 		return
 	}
 
@@ -135,77 +265,105 @@ func UpdateReservation(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation ID"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid reservation ID")
 		return
 	}
 	idUint := uint(idInt)
+	tracing.SetAttributes(c.Request.Context(), tracing.ReservationID.Int64(int64(idUint)))
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
 
 	// Parse updated reservation data
 	var updatedReservation models.Reservation
-	if err := c.ShouldBindJSON(&updatedReservation); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+	if err := h.validate.Bind(c, &updatedReservation); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	// Update reservation
-	if err := reservationHandler.UpdateReservation(idUint, &updatedReservation); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+	if !h.authorize(c, idUint) {
 		return
 	}
+	// only admins may move a reservation to another user
+	if c.GetString("role") != "admin" {
+		updatedReservation.UserID = 0
+	}
 
-	c.JSON(http.StatusOK, updatedReservation)
-}
-
-// @Summary get reservations
-// @Description List reservations based on provided start and end dates
-// @Tags reservation
-// @Produce json
-// @Param start_date query string false "Start date (format: yyyy-mm-dd)"
-// @Param end_date query string false "End date (format: yyyy-mm-dd)"
-// @Security Bearer
-// @Success 200 {array} models.Reservation "List of reservations"
-// @Failure 400 {object} ErrorResponse "Invalid date format"
-// @Failure 403 {object} ErrorResponse "User must be logged in to update a reservation"
-// @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /reservations [get]
-func GetReservations(c *gin.Context) {
-	// Authenticate user
-	userId, _ := c.Get("id")
-	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to view reservations"})
+	// Update reservation
+	err = h.reservations.UpdateReservation(c.Request.Context(), idUint, version, &updatedReservation)
+	if err != nil {
+		problem.Error(c, err, "Failed to update reservation")
 		return
 	}
 
-	// Parse start and end dates
-	startDateStr := c.Query("start_date")
-	endDateStr := c.Query("end_date")
+	respondVersioned(c, http.StatusOK, updatedReservation.Version, updatedReservation)
+}
+
+// parseDateRange parses the optional start_date and end_date query parameters.
+// It writes the error response itself and reports whether parsing succeeded.
+func parseDateRange(c *gin.Context) (startDate, endDate time.Time, ok bool) {
+	var err error
 
 	// Parse start date
-	var startDate time.Time
-	if startDateStr != "" {
-		var err error
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format"})
-			return
+			problem.Respond(c, http.StatusBadRequest, "invalid_date", "Invalid start date format")
+			return startDate, endDate, false
 		}
 	}
 
 	// Parse end date
-	var endDate time.Time
-	if endDateStr != "" {
-		var err error
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format"})
-			return
+			problem.Respond(c, http.StatusBadRequest, "invalid_date", "Invalid end date format")
+			return startDate, endDate, false
 		}
 	}
 
+	return startDate, endDate, true
+}
+
+// @Summary get reservations
+// @Description List the reservations of every user based on provided start and end dates. Only admins may list them, users list their own through /users/{userId}/reservations.
+// @Tags reservation
+// @Produce json
+// @Param start_date query string false "Start date (format: yyyy-mm-dd)"
+// @Param end_date query string false "End date (format: yyyy-mm-dd)"
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, date, status)
+// @Param user_id query int false "Filter by user"
+// @Param food_id query int false "Filter by food"
+// @Param side_id query int false "Filter by side"
+// @Param status query string false "Filter by status" Enums(reserved, served, cancelled)
+// @Param is_paid query bool false "Filter by payment state"
+// @Security Bearer
+// @Success 200 {object} models.Page[models.Reservation] "A page of reservations"
+// @Failure 400 {object} problem.Details "Invalid date format or list parameters"
+// @Failure 403 {object} problem.Details "The caller is not an admin"
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /reservations [get]
+func (h *ReservationHandler) GetReservations(c *gin.Context) {
+	// Parse start and end dates
+	startDate, endDate, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	q, ok := parseListQuery(c, models.ReservationListSpec)
+	if !ok {
+		return
+	}
+
 	// List reservations
-	reservations, err := reservationHandler.ListReservations(startDate, endDate)
+	reservations, err := h.reservations.ListReservations(c.Request.Context(), startDate, endDate, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reservations"})
+		problem.Error(c, err, "Failed to list reservations")
 		return
 	}
 
@@ -217,18 +375,21 @@ func GetReservations(c *gin.Context) {
 // @Tags reservation
 // @Produce json
 // @Param id path int true "Reservation ID"
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.Reservation "The reservation details"
-// @Failure 400 {object} ErrorResponse "Invalid reservation ID format"
-// @Failure 403 {object} ErrorResponse "User must be logged in to update a reservation"
-// @Failure 404 {object} ErrorResponse "Reservation not found"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Success 304 "Not modified, If-None-Match names the current ETag."
+// @Failure 400 {object} problem.Details "Invalid reservation ID format"
+// @Failure 403 {object} problem.Details "The reservation belongs to another user"
+// @Failure 404 {object} problem.Details "Reservation not found"
+// @Failure 500 {object} problem.Details "Internal server error"
 // @Router /reservation/{id} [get]
-func GetReservation(c *gin.Context) {
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
 	// Authenticate user
 	userId, _ := c.Get("id")
 	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to view a reservation"})
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to view a reservation")
 		return
 	}
 
@@ -236,50 +397,111 @@ func GetReservation(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation ID"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid reservation ID")
 		return
 	}
 	idUint := uint(idInt)
+	tracing.SetAttributes(c.Request.Context(), tracing.ReservationID.Int64(int64(idUint)))
 
 	// Get reservation
-	reservation, err := reservationHandler.GetReservation(idUint)
+	reservation, err := h.reservations.GetReservation(c.Request.Context(), idUint)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		problem.Error(c, err, "Error fetching reservation")
+		return
+	}
+	if !owns(c, reservation) {
 		return
 	}
 
-	c.JSON(http.StatusOK, reservation)
+	respondVersioned(c, http.StatusOK, reservation.Version, reservation)
 }
 
 // GetUserReservations retrieves all reservations for a given user ID.
 // @Summary Get User's Reservations
-// @Description Retrieves a list of reservations associated with a specific user.
+// @Description Retrieves a list of reservations associated with a specific user. Users may only list their own reservations, admins those of any user.
 // @Tags reservation
 // @Produce json
 // @Param userId path int true "User ID"
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, date, status)
+// @Param food_id query int false "Filter by food"
+// @Param side_id query int false "Filter by side"
+// @Param status query string false "Filter by status" Enums(reserved, served, cancelled)
+// @Param is_paid query bool false "Filter by payment state"
 // @Security Bearer
-// @Success 200 {array} models.Reservation "An array of reservation objects for the user."
-// @Failure 400 {object} ErrorResponse "Invalid user ID format."
-// @Failure 404 {object} ErrorResponse "Reservations not found for the specified user ID."
+// @Success 200 {object} models.Page[models.Reservation] "A page of reservation objects for the user."
+// @Failure 400 {object} problem.Details "Invalid user ID format or list parameters."
+// @Failure 403 {object} problem.Details "The user is not the caller and the caller is not an admin."
+// @Failure 404 {object} problem.Details "Reservations not found for the specified user ID."
 // @Router /users/{userId}/reservations [get]
-func GetUserReservations(c *gin.Context) {
+func (h *ReservationHandler) GetUserReservations(c *gin.Context) {
 	userID := c.Param("id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid user ID format")
 		return
 	}
 
 	uid, err := strconv.ParseUint(userID, 10, 32) // Convert userID from string to uint
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing user ID"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Error parsing user ID")
+		return
+	}
+	if c.GetString("role") != "admin" && uint(uid) != c.GetUint("id") {
+		problem.Respond(c, http.StatusForbidden, "forbidden", "You can only access your own reservations")
+		return
+	}
+
+	q, ok := parseListQuery(c, models.ReservationListSpec)
+	if !ok {
 		return
 	}
 
-	reservations, err := reservationHandler.GetReservationsByUserID(uint(uid)) // Correctly cast to uint now
+	reservations, err := h.reservations.GetReservationsByUserID(c.Request.Context(), uint(uid), q) // Correctly cast to uint now
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations for user"})
+		problem.Error(c, err, "Error fetching reservations for user")
 		return
 	}
 
 	c.JSON(http.StatusOK, reservations)
 }
+
+// @Summary Mark a reservation as served
+// @Description Marks a reservation as served once the meal was handed out. Served reservations can be reviewed by their owner.
+// @Tags reservation
+// @Param id path int true "Reservation ID"
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 204 "Reservation marked as served"
+// @Failure 400 {object} problem.Details "Invalid reservation ID format"
+// @Failure 404 {object} problem.Details "Reservation not found"
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /reservations/{id}/serve [put]
+func (h *ReservationHandler) ServeReservation(c *gin.Context) {
+	// Parse reservation ID
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid reservation ID")
+		return
+	}
+	idUint := uint(idInt)
+	tracing.SetAttributes(c.Request.Context(), tracing.ReservationID.Int64(int64(idUint)))
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.reservations.MarkServed(c.Request.Context(), idUint, version)
+	if err != nil {
+		problem.Error(c, err, "Failed to update reservation")
+		return
+	}
+	h.metrics.ReservationsServed.WithLabelValues(h.mealType(c, idUint)).Inc()
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler handles the review endpoints.
+type ReviewHandler struct {
+	reviews  models.ReviewStore
+	validate *validation.Validator
+}
+
+func NewReviewHandler(reviews models.ReviewStore, validate *validation.Validator) *ReviewHandler {
+	return &ReviewHandler{reviews, validate}
+}
+
+type ReviewRequest struct {
+	FoodRating int    `json:"food_rating" binding:"required,min=1,max=5" example:"5"`
+	SideRating int    `json:"side_rating" binding:"omitempty,min=1,max=5" example:"4"`
+	Comment    string `json:"comment" binding:"max=1000" example:"Tasty and warm"`
+}
+
+// @Summary Review a reservation
+// @Description Rates the food and side (1-5) of a served reservation and leaves an optional comment. Each reservation can be reviewed once by its owner.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Param review body ReviewRequest true "Ratings and comment"
+// @Security Bearer
+// @Success 201 {object} models.Review "The created review."
+// @Failure 400 {object} problem.Details "Invalid request format or rating out of range."
+// @Failure 403 {object} problem.Details "The reservation belongs to another user."
+// @Failure 404 {object} problem.Details "Reservation not found."
+// @Failure 409 {object} problem.Details "The reservation is not served yet or was already reviewed."
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /reservations/{id}/review [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	userId, _ := c.Get("id")
+	if userId == nil {
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to review a reservation")
+		return
+	}
+
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid reservation ID")
+		return
+	}
+
+	var request ReviewRequest
+	if err := h.validate.Bind(c, &request); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	review := models.Review{
+		FoodRating: request.FoodRating,
+		SideRating: request.SideRating,
+		Comment:    request.Comment,
+	}
+
+	err = h.reviews.CreateReview(c.Request.Context(), userId.(uint), uint(idInt), &review)
+	if err != nil {
+		problem.Error(c, err, "Failed to create review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// @Summary Get reviews of a food
+// @Description Retrieves the visible reviews of a food dish, newest first.
+// @Tags review
+// @Produce json
+// @Param id path int true "Food ID" Format(int64)
+// @Security Bearer
+// @Success 200 {array} models.Review "An array of reviews."
+// @Failure 400 {object} problem.Details "Invalid food ID format."
+// @Failure 500 {object} problem.Details "Internal server error while fetching reviews."
+// @Router /food/{id}/reviews [get]
+func (h *ReviewHandler) GetFoodReviews(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid food id")
+		return
+	}
+
+	reviews, err := h.reviews.GetFoodReviews(c.Request.Context(), uint(idInt))
+	if err != nil {
+		problem.Error(c, err, "Error fetching reviews!")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// @Summary Get reviews of a side dish
+// @Description Retrieves the visible reviews of a side dish, newest first.
+// @Tags review
+// @Produce json
+// @Param id path int true "Sides ID" Format(int64)
+// @Security Bearer
+// @Success 200 {array} models.Review "An array of reviews."
+// @Failure 400 {object} problem.Details "Invalid sides ID format."
+// @Failure 500 {object} problem.Details "Internal server error while fetching reviews."
+// @Router /sides/{id}/reviews [get]
+func (h *ReviewHandler) GetSideReviews(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid sides id")
+		return
+	}
+
+	reviews, err := h.reviews.GetSideReviews(c.Request.Context(), uint(idInt))
+	if err != nil {
+		problem.Error(c, err, "Error fetching reviews!")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// @Summary Get all reviews
+// @Description Retrieves all reviews for moderation, including hidden ones.
+// @Tags review
+// @Produce json
+// @Param hidden query bool false "Only return hidden (true) or visible (false) reviews"
+// @Security Bearer
+// @Success 200 {array} models.Review "An array of reviews."
+// @Failure 400 {object} problem.Details "Invalid hidden filter."
+// @Failure 500 {object} problem.Details "Internal server error while fetching reviews."
+// @Router /reviews [get]
+func (h *ReviewHandler) GetReviews(c *gin.Context) {
+	var hidden *bool
+	if hiddenStr := c.Query("hidden"); hiddenStr != "" {
+		value, err := strconv.ParseBool(hiddenStr)
+		if err != nil {
+			problem.Respond(c, http.StatusBadRequest, "invalid_query", "Invalid hidden filter")
+			return
+		}
+		hidden = &value
+	}
+
+	reviews, err := h.reviews.GetReviews(c.Request.Context(), hidden)
+	if err != nil {
+		problem.Error(c, err, "Error fetching reviews!")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// @Summary Hide a review comment
+// @Description Hides a review from the public review lists. The rating still counts towards the aggregates.
+// @Tags review
+// @Param id path int true "Review ID" Format(int64)
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 204 "Review hidden."
+// @Failure 400 {object} problem.Details "Invalid review ID format."
+// @Failure 404 {object} problem.Details "Review not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while updating the review."
+// @Router /reviews/{id}/hide [put]
+func (h *ReviewHandler) HideReview(c *gin.Context) {
+	h.setReviewHidden(c, true)
+}
+
+// @Summary Show a hidden review comment
+// @Description Makes a previously hidden review visible again.
+// @Tags review
+// @Param id path int true "Review ID" Format(int64)
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 204 "Review visible."
+// @Failure 400 {object} problem.Details "Invalid review ID format."
+// @Failure 404 {object} problem.Details "Review not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while updating the review."
+// @Router /reviews/{id}/unhide [put]
+func (h *ReviewHandler) UnhideReview(c *gin.Context) {
+	h.setReviewHidden(c, false)
+}
+
+func (h *ReviewHandler) setReviewHidden(c *gin.Context, hidden bool) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid review id")
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.reviews.SetReviewHidden(c.Request.Context(), uint(idInt), version, hidden)
+	if err != nil {
+		problem.Error(c, err, "Error updating review")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler handles the search endpoint.
+type SearchHandler struct {
+	search models.SearchStore
+}
+
+func NewSearchHandler(search models.SearchStore) *SearchHandler {
+	return &SearchHandler{search}
+}
+
+// @Summary Search foods, sides and users
+// @Description Full-text search over food and side names and descriptions, and over user names, emails and telephones. Falls back to fuzzy matching when nothing matches exactly.
+// @Description Users can only be searched by admins. Snippets are HTML: the text is escaped and matches are highlighted with <mark> tags.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search text"
+// @Param type query string false "Comma separated resource types to search, defaults to food,side (and user for admins)" Enums(food, side, user)
+// @Param limit query int false "Maximum number of results (1-100)" default(20)
+// @Security Bearer
+// @Success 200 {array} models.SearchResult "Matches ordered by rank."
+// @Failure 400 {object} problem.Details "Missing search text or invalid parameters."
+// @Failure 403 {object} problem.Details "Only admins can search users."
+// @Failure 500 {object} problem.Details "Internal server error while searching."
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		problem.Respond(c, http.StatusBadRequest, "invalid_query", "Missing search text")
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			problem.Respond(c, http.StatusBadRequest, "invalid_query", "Limit must be between 1 and 100")
+			return
+		}
+	}
+
+	isAdmin := c.GetString("role") == "admin"
+
+	types := []string{models.SearchFood, models.SearchSide}
+	if isAdmin {
+		types = append(types, models.SearchUser)
+	}
+	if typeStr := c.Query("type"); typeStr != "" {
+		types = strings.Split(typeStr, ",")
+		for _, t := range types {
+			switch t {
+			case models.SearchFood, models.SearchSide:
+			case models.SearchUser:
+				if !isAdmin {
+					problem.Respond(c, http.StatusForbidden, "forbidden", "Only admins can search users")
+					return
+				}
+			default:
+				problem.Respond(c, http.StatusBadRequest, "invalid_query", "Invalid type "+t)
+				return
+			}
+		}
+	}
+
+	results, err := h.search.Search(c.Request.Context(), query, types, limit)
+	if err != nil {
+		problem.Error(c, err, "Error searching!")
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
@@ -5,14 +5,19 @@ import (
 	"strconv"
 
 	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-var sidesHandler *models.SidesHandler
+// SidesHandler handles the sides endpoints.
+type SidesHandler struct {
+	sides    models.SidesStore
+	validate *validation.Validator
+}
 
-func InitializedSidesHandler(db *gorm.DB) {
-	sidesHandler = models.NewSidesHandler(db)
+func NewSidesHandler(sides models.SidesStore, validate *validation.Validator) *SidesHandler {
+	return &SidesHandler{sides, validate}
 }
 
 // @Summary Get a Single Side Dish
@@ -20,44 +25,58 @@ func InitializedSidesHandler(db *gorm.DB) {
 // @Tags sides
 // @Produce json
 // @Param id path int true "Sides ID" Format(int64)
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.Sides "The details of the sides including ID, name, quantity."
-// @Failure 400 {object} ErrorResponse "Invalid sides ID format."
-// @Failure 404 {object} ErrorResponse "Sides not found with the specified ID."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Success 304 "Not modified, If-None-Match names the current ETag."
+// @Failure 400 {object} problem.Details "Invalid sides ID format."
+// @Failure 404 {object} problem.Details "Sides not found with the specified ID."
 // @Router /sides/{id} [get]
-func GetSide(c *gin.Context) {
+func (h *SidesHandler) GetSide(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sides id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid sides id")
 		return
 	}
 
 	idUint := uint(idInt)
 
-	user, err := sidesHandler.GetSide(idUint)
+	user, err := h.sides.GetSide(c.Request.Context(), idUint)
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Side Dish not found"})
+		problem.Error(c, err, "Error fetching side dish")
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	respondVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // @Summary Get All Sides
-// @Description Retrieves a list of all side dishes in the system.
+// @Description Retrieves a page of side dishes in the system.
 // @Tags sides
 // @Produce json
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, name, rating_average, rating_count)
+// @Param name query string false "Filter by name"
 // @Security Bearer
-// @Success 200 {array} models.Sides "An array of sides objects."
-// @Failure 500 {object} ErrorResponse "Internal server error while fetching sides."
+// @Success 200 {object} models.Page[models.Sides] "A page of sides objects."
+// @Failure 400 {object} problem.Details "Invalid pagination, sort or filter parameters."
+// @Failure 500 {object} problem.Details "Internal server error while fetching sides."
 // @Router /sides [get]
-func GetSides(c *gin.Context) {
-	sides, err := sidesHandler.GetSides()
+func (h *SidesHandler) GetSides(c *gin.Context) {
+	q, ok := parseListQuery(c, models.SidesListSpec)
+	if !ok {
+		return
+	}
+
+	sides, err := h.sides.GetSides(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching sides!"})
+		problem.Error(c, err, "Error fetching sides!")
 		return
 	}
 
@@ -72,23 +91,23 @@ func GetSides(c *gin.Context) {
 // @Param sides body models.Sides true "Sides Details"
 // @Security Bearer
 // @Success 201 {object} models.Sides "The created Side's details, including their unique identifier."
-// @Failure 400 {object} ErrorResponse "Invalid input format for Sides."
-// @Failure 500 {object} ErrorResponse "Internal server error while creating the sides."
+// @Failure 400 {object} problem.Details "Invalid input format for Sides."
+// @Failure 500 {object} problem.Details "Internal server error while creating the sides."
 // @Router /sides [post]
-func CreateSides(c *gin.Context) {
+func (h *SidesHandler) CreateSides(c *gin.Context) {
 	var side models.Sides
 
-	if err := c.ShouldBindJSON(&side); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &side); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	if err := sidesHandler.CreateSides(&side); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating side dish!"})
+	if err := h.sides.CreateSides(c.Request.Context(), &side); err != nil {
+		problem.Error(c, err, "Error creating side dish!")
 		return
 	}
 
-	c.JSON(http.StatusCreated, side)
+	respondVersioned(c, http.StatusCreated, side.Version, side)
 }
 
 // @Summary Update a Side Dish
@@ -98,34 +117,101 @@ func CreateSides(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Side ID" Format(int64)
 // @Param sides body models.Sides true "Updated Sides Details"
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.Sides "The updated side's details."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details or invalid sides ID."
-// @Failure 500 {object} ErrorResponse "Internal server error while updating the sides."
+// @Failure 400 {object} problem.Details "Invalid input format for user details or invalid sides ID."
+// @Failure 404 {object} problem.Details "Side dish not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while updating the sides."
 // @Router /sides/{id} [put]
-func UpdateSides(c *gin.Context) {
+func (h *SidesHandler) UpdateSides(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid side id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid side id")
 		return
 	}
 	idUint := uint(idInt)
 
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
 	var side models.Sides
 
-	if err := c.ShouldBindJSON(&side); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &side); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	err = sidesHandler.UpdateSides(idUint, &side)
+	err = h.sides.UpdateSides(c.Request.Context(), idUint, version, &side)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating side dish"})
+		problem.Error(c, err, "Error updating side dish")
 		return
 	}
 
-	c.JSON(http.StatusOK, side)
+	respondVersioned(c, http.StatusOK, side.Version, side)
+}
+
+// @Summary Patch a side dish
+// @Description Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name, quantity, description.
+// @Tags sides
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Side dish ID" Format(int64)
+// @Param patch body object true "Fields to change"
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 200 {object} models.Sides "The patched side dish."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Failure 400 {object} problem.Details "Invalid side dish ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date."
+// @Failure 403 {object} problem.Details "The patch changes fields only admins may change."
+// @Failure 404 {object} problem.Details "Side dish not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 415 {object} problem.Details "Unsupported patch format."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while patching the side dish."
+// @Router /sides/{id} [patch]
+func (h *SidesHandler) PatchSides(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid side dish id")
+		return
+	}
+	idUint := uint(idInt)
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, ok := parsePatch(c, models.SidesPatchSpec)
+	if !ok {
+		return
+	}
+
+	current, err := h.sides.GetSide(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error patching side dish")
+		return
+	}
+	if !validPatch(c, h.validate, current, changes) {
+		return
+	}
+
+	side, err := h.sides.PatchSides(c.Request.Context(), idUint, version, changes)
+	if err != nil {
+		problem.Error(c, err, "Error patching side dish")
+		return
+	}
+
+	respondVersioned(c, http.StatusOK, side.Version, side)
 }
 
 // @Summary Delete a Side Dish
@@ -133,23 +219,32 @@ func UpdateSides(c *gin.Context) {
 // @Tags sides
 // @Produce json
 // @Param id path int true "Sides ID" Format(int64)
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 204 "Side Dish successfully deleted, no content to return."
-// @Failure 400 {object} ErrorResponse "Invalid sides ID format."
-// @Failure 500 {object} ErrorResponse "Internal server error while deleting the sides."
+// @Failure 400 {object} problem.Details "Invalid sides ID format."
+// @Failure 404 {object} problem.Details "Side dish not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while deleting the sides."
 // @Router /sides/{id} [delete]
-func DeleteSides(c *gin.Context) {
+func (h *SidesHandler) DeleteSides(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sides id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid sides id")
 		return
 	}
 	idUint := uint(idInt)
 
-	err = sidesHandler.DeleteSides(idUint)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.sides.DeleteSides(c.Request.Context(), idUint, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting sides"})
+		problem.Error(c, err, "Error deleting sides")
 		return
 	}
 
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler handles the endpoints of soft-deleted rows.
+type TrashHandler struct {
+	trash     models.TrashStore
+	retention time.Duration
+}
+
+// NewTrashHandler returns a handler that only purges rows deleted longer than
+// retention ago.
+func NewTrashHandler(trash models.TrashStore, retention time.Duration) *TrashHandler {
+	return &TrashHandler{trash, retention}
+}
+
+// PurgeResponse counts the purged rows per resource.
+type PurgeResponse struct {
+	Purged map[string]int64 `json:"purged"`
+	// Before is the time rows were deleted before to be purged
+	Before time.Time `json:"before"`
+}
+
+// @Summary List deleted rows
+// @Description Lists the soft-deleted rows of a resource with the time they were deleted.
+// @Tags trash
+// @Produce json
+// @Param resource path string true "Resource" Enums(reviews, reservations, foods, sides, categories, mealtypes, users)
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, deleted_at)
+// @Security Bearer
+// @Success 200 {object} models.Page[models.TrashItem] "A page of deleted rows"
+// @Failure 400 {object} problem.Details "Invalid pagination or sort parameters"
+// @Failure 404 {object} problem.Details "Unknown resource"
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /trash/{resource} [get]
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	q, ok := parseListQuery(c, models.TrashListSpec)
+	if !ok {
+		return
+	}
+
+	items, err := h.trash.ListTrash(c.Request.Context(), c.Param("resource"), q)
+	if err != nil {
+		problem.Error(c, err, "Failed to list deleted rows")
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Restore a deleted row
+// @Description Undeletes a soft-deleted row. Rows it references must not be deleted, for example a food is only restored once its category and meal type are.
+// @Tags trash
+// @Produce json
+// @Param resource path string true "Resource" Enums(reviews, reservations, foods, sides, categories, mealtypes, users)
+// @Param id path int true "Row ID" Format(int64)
+// @Security Bearer
+// @Success 204 "Row restored, no content to return."
+// @Failure 400 {object} problem.Details "Invalid ID"
+// @Failure 404 {object} problem.Details "Unknown resource or no such deleted row"
+// @Failure 409 {object} problem.Details "A referenced row is deleted or was purged"
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /trash/{resource}/{id}/restore [post]
+func (h *TrashHandler) RestoreTrash(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+
+	if err := h.trash.Restore(c.Request.Context(), c.Param("resource"), uint(id)); err != nil {
+		problem.Error(c, err, "Failed to restore row")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Purge deleted rows
+// @Description Permanently deletes the rows of a resource that were deleted longer than the trash retention ago. Rows still referenced by other rows, such as a food with reservations, are kept.
+// @Tags trash
+// @Produce json
+// @Param resource path string true "Resource" Enums(reviews, reservations, foods, sides, categories, mealtypes, users)
+// @Security Bearer
+// @Success 200 {object} PurgeResponse "Number of purged rows"
+// @Failure 404 {object} problem.Details "Unknown resource"
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /trash/{resource} [delete]
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	resource, before := c.Param("resource"), time.Now().Add(-h.retention)
+	count, err := h.trash.Purge(c.Request.Context(), resource, before)
+	if err != nil {
+		problem.Error(c, err, "Failed to purge deleted rows")
+		return
+	}
+	c.JSON(http.StatusOK, PurgeResponse{Purged: map[string]int64{resource: count}, Before: before})
+}
+
+// @Summary Purge all deleted rows
+// @Description Permanently deletes the rows of every resource that were deleted longer than the trash retention ago, in one transaction. Rows still referenced by other rows are kept.
+// @Tags trash
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} PurgeResponse "Number of purged rows per resource"
+// @Failure 500 {object} problem.Details "Internal server error"
+// @Router /trash [delete]
+func (h *TrashHandler) PurgeAllTrash(c *gin.Context) {
+	before := time.Now().Add(-h.retention)
+	purged, err := h.trash.PurgeAll(c.Request.Context(), before)
+	if err != nil {
+		problem.Error(c, err, "Failed to purge deleted rows")
+		return
+	}
+	c.JSON(http.StatusOK, PurgeResponse{Purged: purged, Before: before})
+}
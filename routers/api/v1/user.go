@@ -3,23 +3,25 @@ package v1
 import (
 	"fmt"
 	"net/http"
-	"os"
 	"strconv"
 
 	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/problem"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/skip2/go-qrcode"
-	"gorm.io/gorm"
 )
 
-var userHandler *models.UserHandler
-
-type ErrorResponse struct {
-	Error string `json:"error" example:"Description of the error occurred"`
+// UserHandler handles the user endpoints.
+type UserHandler struct {
+	users models.UserStore
+	// baseURL is the public host of the API that QR codes link to
+	baseURL  string
+	validate *validation.Validator
 }
 
-func InitializedUserHandler(db *gorm.DB) {
-	userHandler = models.NewUserHandler(db)
+func NewUserHandler(users models.UserStore, baseURL string, validate *validation.Validator) *UserHandler {
+	return &UserHandler{users, baseURL, validate}
 }
 
 // @Summary Get a Single User
@@ -27,45 +29,61 @@ func InitializedUserHandler(db *gorm.DB) {
 // @Tags user
 // @Produce json
 // @Param id path int true "User ID" Format(int64)
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.User "The details of the user including ID, name, email, telephone, and role."
-// @Failure 400 {object} ErrorResponse "Invalid user ID format."
-// @Failure 404 {object} ErrorResponse "User not found with the specified ID."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Success 304 "Not modified, If-None-Match names the current ETag."
+// @Failure 400 {object} problem.Details "Invalid user ID format."
+// @Failure 404 {object} problem.Details "User not found with the specified ID."
 // @Router /users/{id} [get]
-func GetUser(c *gin.Context) {
+func (h *UserHandler) GetUser(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid user id")
 		return
 	}
 
 	idUint := uint(idInt)
 
-	user, err := userHandler.GetUser(idUint)
+	user, err := h.users.GetUser(c.Request.Context(), idUint)
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		problem.Error(c, err, "Error fetching user")
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	respondVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // @Summary Get All Users
-// @Description Retrieves a list of all users in the system.
+// @Description Retrieves a page of users in the system.
 // @Tags user
 // @Produce json
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Param page query int false "Page number, starting at 1"
+// @Param cursor query string false "Cursor from meta.next_cursor, only when sorting by id"
+// @Param sort query string false "Comma separated sort keys, prefix with - for descending" Enums(id, name, email, role, created_at)
+// @Param email query string false "Filter by email"
+// @Param telephone query string false "Filter by telephone"
+// @Param role query string false "Filter by role"
 // @Security Bearer
-// @Success 200 {array} models.User "An array of user objects."
-// @Failure 500 {object} ErrorResponse "Internal server error while fetching users."
+// @Success 200 {object} models.Page[models.User] "A page of user objects."
+// @Failure 400 {object} problem.Details "Invalid pagination, sort or filter parameters."
+// @Failure 500 {object} problem.Details "Internal server error while fetching users."
 // @Router /users [get]
-func GetUsers(c *gin.Context) {
-	users, err := userHandler.GetUsers()
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	q, ok := parseListQuery(c, models.UserListSpec)
+	if !ok {
+		return
+	}
+
+	users, err := h.users.GetUsers(c.Request.Context(), q)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching users!"})
+		problem.Error(c, err, "Error fetching users!")
 		return
 	}
 
@@ -77,63 +95,146 @@ func GetUsers(c *gin.Context) {
 // @Tags user
 // @Accept json
 // @Produce json
-// @Param user body models.User true "User Registration Details"
+// @Param user body models.NewUser true "User Registration Details"
 // @Security Bearer
 // @Success 201 {object} models.User "The created user's details, including their unique identifier."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details."
-// @Failure 500 {object} ErrorResponse "Internal server error while creating the user."
+// @Failure 400 {object} problem.Details "Invalid input format for user details."
+// @Failure 500 {object} problem.Details "Internal server error while creating the user."
 // @Router /users [post]
-func CreateUser(c *gin.Context) {
-	var user models.User
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var details models.NewUser
 
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.validate.Bind(c, &details); err != nil {
+		problem.Bind(c, err)
 		return
 	}
 
-	if err := userHandler.CreateUser(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating user!"})
+	user := models.User{Name: details.Name, Email: details.Email, Telephone: details.Telephone, Role: details.Role, Password: details.Password}
+	if err := h.users.CreateUser(c.Request.Context(), &user); err != nil {
+		problem.Error(c, err, "Error creating user!")
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	respondVersioned(c, http.StatusCreated, user.Version, user)
 }
 
 // @Summary Update a User
-// @Description Updates the details of an existing user identified by their ID.
+// @Description Updates the details of an existing user identified by their ID. Users may only update their own name, email and telephone, admins may update any user including the role.
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID" Format(int64)
-// @Param user body models.User true "Updated User Details"
+// @Param user body models.AdminUserDetails true "Updated User Details, role is only written for admins"
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.User "The updated user's details."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details or invalid user ID."
-// @Failure 500 {object} ErrorResponse "Internal server error while updating the user."
+// @Failure 400 {object} problem.Details "Invalid input format for user details or invalid user ID."
+// @Failure 403 {object} problem.Details "The user is not the caller and the caller is not an admin."
+// @Failure 404 {object} problem.Details "User not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while updating the user."
 // @Router /users/{id} [put]
-func UpdateUser(c *gin.Context) {
+func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid user id")
 		return
 	}
 	idUint := uint(idInt)
 
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
 	var user models.User
+	if c.GetString("role") == "admin" {
+		var details models.AdminUserDetails
+		if err := h.validate.Bind(c, &details); err != nil {
+			problem.Bind(c, err)
+			return
+		}
+		user = models.User{Name: details.Name, Email: details.Email, Telephone: details.Telephone, Role: details.Role}
+	} else {
+		if c.GetUint("id") != idUint {
+			problem.Respond(c, http.StatusForbidden, "forbidden", "You can only update your own account")
+			return
+		}
+		var details models.UserDetails
+		if err := h.validate.Bind(c, &details); err != nil {
+			problem.Bind(c, err)
+			return
+		}
+		user = models.User{Name: details.Name, Email: details.Email, Telephone: details.Telephone}
+	}
 
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	err = h.users.UpdateUser(c.Request.Context(), idUint, version, &user)
+	if err != nil {
+		problem.Error(c, err, "Error updating user")
 		return
 	}
 
-	err = userHandler.UpdateUser(idUint, &user)
+	respondVersioned(c, http.StatusOK, user.Version, user)
+}
+
+// @Summary Patch a user
+// @Description Changes only the fields present in the body, including setting them to zero values. Accepts a JSON merge patch (RFC 7396), where null resets a field, or a JSON patch (RFC 6902) with add, replace and remove operations. Patchable fields: name, email, telephone and, for admins, role.
+// @Tags user
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @Param patch body object true "Fields to change"
+// @Param If-Match header string true "ETag from a previous read"
+// @Security Bearer
+// @Success 200 {object} models.User "The patched user."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Failure 400 {object} problem.Details "Invalid user ID, a patch with unknown fields or invalid values, or a patched resource that fails validation, such as a missing reference or a past date."
+// @Failure 403 {object} problem.Details "The patch changes fields only admins may change."
+// @Failure 404 {object} problem.Details "User not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 415 {object} problem.Details "Unsupported patch format."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while patching the user."
+// @Router /users/{id} [patch]
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid user id")
 		return
 	}
+	idUint := uint(idInt)
 
-	c.JSON(http.StatusOK, user)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, ok := parsePatch(c, models.UserPatchSpec)
+	if !ok {
+		return
+	}
+
+	current, err := h.users.GetUser(c.Request.Context(), idUint)
+	if err != nil {
+		problem.Error(c, err, "Error patching user")
+		return
+	}
+	if !validPatch(c, h.validate, current, changes) {
+		return
+	}
+
+	user, err := h.users.PatchUser(c.Request.Context(), idUint, version, changes)
+	if err != nil {
+		problem.Error(c, err, "Error patching user")
+		return
+	}
+
+	respondVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // @Summary Delete a User
@@ -141,23 +242,32 @@ func UpdateUser(c *gin.Context) {
 // @Tags user
 // @Produce json
 // @Param id path int true "User ID" Format(int64)
+// @Param If-Match header string true "ETag from a previous read"
 // @Security Bearer
 // @Success 204 "User successfully deleted, no content to return."
-// @Failure 400 {object} ErrorResponse "Invalid user ID format."
-// @Failure 500 {object} ErrorResponse "Internal server error while deleting the user."
+// @Failure 400 {object} problem.Details "Invalid user ID format."
+// @Failure 404 {object} problem.Details "User not found."
+// @Failure 412 {object} problem.Details "The resource changed since the If-Match ETag was read."
+// @Failure 428 {object} problem.Details "If-Match header is missing."
+// @Failure 500 {object} problem.Details "Internal server error while deleting the user."
 // @Router /users/{id} [delete]
-func DeleteUser(c *gin.Context) {
+func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idString := c.Param("id")
 	idInt, err := strconv.Atoi(idString)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		problem.Respond(c, http.StatusBadRequest, "invalid_id", "Invalid user id")
 		return
 	}
 	idUint := uint(idInt)
 
-	err = userHandler.DeleteUser(idUint)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.users.DeleteUser(c.Request.Context(), idUint, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting user"})
+		problem.Error(c, err, "Error deleting user")
 		return
 	}
 
@@ -168,22 +278,25 @@ func DeleteUser(c *gin.Context) {
 // @Description Retrieves the details of the currently authenticated user.
 // @Tags user
 // @Produce json
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Security Bearer
 // @Success 200 {object} models.User "The details of the currently authenticated user."
-// @Failure 404 {object} ErrorResponse "User not found."
+// @Header 200 {string} ETag "Version of the resource, send it as If-Match to update or delete it"
+// @Success 304 "Not modified, If-None-Match names the current ETag."
+// @Failure 404 {object} problem.Details "User not found."
 // @Router /me [get]
-func GetMe(c *gin.Context) {
+func (h *UserHandler) GetMe(c *gin.Context) {
 	userId, _ := c.Get("id")
 	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to view reservations"})
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to view reservations")
 		return
 	}
-	user, err := userHandler.GetUser(userId.(uint))
+	user, err := h.users.GetUser(c.Request.Context(), userId.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		problem.Error(c, err, "Error fetching user")
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	respondVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // @Summary Get my profile QR CODE
@@ -192,20 +305,20 @@ func GetMe(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Success 200 {object} models.User "The QR CODE of the currently authenticated user."
-// @Failure 404 {object} ErrorResponse "User not found."
+// @Failure 404 {object} problem.Details "User not found."
 // @Router /me/qr [get]
-func GetMeQR(c *gin.Context) {
+func (h *UserHandler) GetMeQR(c *gin.Context) {
 	userId, _ := c.Get("id")
 	if userId == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be logged in to view reservations"})
+		problem.Respond(c, http.StatusUnauthorized, "unauthorized", "User must be logged in to view reservations")
 		return
 	}
-	urlToUser := fmt.Sprintf("http://%s/api/v1/users/%v", os.Getenv("BASE_URL"), userId)
+	urlToUser := fmt.Sprintf("http://%s/api/v1/users/%v", h.baseURL, userId)
 
 	// Generate QR code
 	qrCode, err := qrcode.Encode(urlToUser, qrcode.Medium, 256)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+		problem.Error(c, err, "Failed to generate QR code")
 		return
 	}
 
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/gin-gonic/gin"
+)
+
+// users records the user written by UpdateUser.
+type users struct {
+	models.UserStore
+	updated *models.User
+}
+
+func (u *users) UpdateUser(ctx context.Context, id uint, version models.Version, user *models.User) error {
+	u.updated = user
+	user.ID, user.Version = id, version+1
+	return nil
+}
+
+func updateUser(store *users, role string, caller uint, path, body string) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.PUT("/users/:id", func(c *gin.Context) {
+		c.Set("id", caller)
+		c.Set("role", role)
+	}, NewUserHandler(store, "", validate).UpdateUser)
+
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	body := `{"name":"a","email":"a@example.com","role":"admin"}`
+
+	store := &users{}
+	if w := updateUser(store, "user", 1, "/users/1", body); w.Code != http.StatusOK {
+		t.Fatalf("own update = %d %s", w.Code, w.Body)
+	}
+	if store.updated.Role != "" {
+		t.Errorf("user set their role to %q", store.updated.Role)
+	}
+
+	store = &users{}
+	if w := updateUser(store, "user", 2, "/users/1", body); w.Code != http.StatusForbidden || store.updated != nil {
+		t.Errorf("update of another user = %d, want 403", w.Code)
+	}
+
+	store = &users{}
+	if w := updateUser(store, "admin", 2, "/users/1", body); w.Code != http.StatusOK || store.updated.Role != "admin" {
+		t.Errorf("admin update = %d with role %+v", w.Code, store.updated)
+	}
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	store := &users{}
+	w := updateUser(store, "admin", 2, "/users/1", `{"name":"a","email":"a@example.com","password":"plaintext123"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin update = %d %s", w.Code, w.Body)
+	}
+	if store.updated.Password != "" {
+		t.Errorf("admin update wrote the password %q", store.updated.Password)
+	}
+	if strings.Contains(w.Body.String(), "password") {
+		t.Errorf("response contains the password: %s", w.Body)
+	}
+}
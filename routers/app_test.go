@@ -0,0 +1,84 @@
+package routers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/app"
+	"github.com/Hamedblue1381/restaurant-reserve/config"
+	"github.com/Hamedblue1381/restaurant-reserve/health"
+	"github.com/Hamedblue1381/restaurant-reserve/metrics"
+	"github.com/Hamedblue1381/restaurant-reserve/middleware"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/routers"
+)
+
+// fakeFoods keeps foods in a map. Methods the tests do not use panic through
+// the embedded nil interface.
+type fakeFoods struct {
+	models.FoodStore
+	foods map[uint]*models.Food
+}
+
+func (f *fakeFoods) GetFood(ctx context.Context, id uint) (*models.Food, error) {
+	food, ok := f.foods[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return food, nil
+}
+
+func (f *fakeFoods) CreateFood(ctx context.Context, food *models.Food) error {
+	food.ID, food.Version = uint(len(f.foods)+1), 1
+	f.foods[food.ID] = food
+	return nil
+}
+
+// fakeMealTypes only knows the meal type with ID 1.
+type fakeMealTypes struct{ models.MealTypeStore }
+
+func (fakeMealTypes) GetMealType(ctx context.Context, id uint) (*models.MealType, error) {
+	if id != 1 {
+		return nil, models.ErrNotFound
+	}
+	return &models.MealType{Model: models.Model{ID: id}, Name: "Lunch"}, nil
+}
+
+func TestFakeStores(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "secret"
+	foods := &fakeFoods{foods: map[uint]*models.Food{1: {Model: models.Model{ID: 1, Version: 3}, Name: "Kebab"}}}
+	a := &app.App{
+		Config:    cfg,
+		Tokens:    middleware.NewJWT(cfg.Auth.JWTSecret, time.Hour),
+		Metrics:   metrics.New(),
+		Health:    health.New(time.Second),
+		Foods:     foods,
+		MealTypes: fakeMealTypes{},
+	}
+	s := &server{t, routers.UseRouter(a)}
+	admin, err := a.Tokens.GenerateToken("admin@example.com", 1, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var food models.Food
+	if code := s.do(http.MethodGet, "/api/v1/food/1", admin, 0, "", &food); code != http.StatusOK || food.Name != "Kebab" || food.Version != 3 {
+		t.Errorf("get food = %d %+v", code, food)
+	}
+	if code := s.do(http.MethodGet, "/api/v1/food/2", admin, 0, "", nil); code != http.StatusNotFound {
+		t.Errorf("get missing food = %d, want 404", code)
+	}
+
+	// the exists rule looks meal types up in the fake store of this App
+	body := `{"name":"Soup","CategoryID":1,"MealTypeID":%d}`
+	if code := s.do(http.MethodPost, "/api/v1/food", admin, 0, fmt.Sprintf(body, 2), nil); code != http.StatusBadRequest {
+		t.Errorf("create food with a missing meal type = %d, want 400", code)
+	}
+	if code := s.do(http.MethodPost, "/api/v1/food", admin, 0, fmt.Sprintf(body, 1), nil); code != http.StatusCreated || foods.foods[2] == nil {
+		t.Errorf("create food = %d, stored %+v", code, foods.foods)
+	}
+}
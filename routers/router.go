@@ -1,73 +1,142 @@
 package routers
 
 import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/app"
 	"github.com/Hamedblue1381/restaurant-reserve/config"
 	docs "github.com/Hamedblue1381/restaurant-reserve/docs"
 	"github.com/Hamedblue1381/restaurant-reserve/middleware"
 	"github.com/Hamedblue1381/restaurant-reserve/routers/api"
 	v1 "github.com/Hamedblue1381/restaurant-reserve/routers/api/v1"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // @securityDefinitions.apikey Bearer
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
-func UseRouter() *gin.Engine {
+func UseRouter(a *app.App) *gin.Engine {
+	tokens := a.Tokens
+	// the stores of this App answer the exists rules of request bodies
+	validate := validation.New(validation.Stores{Foods: a.Foods, Sides: a.Sides, MealTypes: a.MealTypes})
+	reservations := v1.NewReservationHandler(a.Reservations, a.Metrics, validate)
+	foods := v1.NewFoodHandler(a.Foods, validate)
+	mealTypes := v1.NewMealTypeHandler(a.MealTypes, validate)
+	sides := v1.NewSidesHandler(a.Sides, validate)
+	users := v1.NewUserHandler(a.Users, a.Config.Server.BaseURL, validate)
+	reviews := v1.NewReviewHandler(a.Reviews, validate)
+	reports := v1.NewReportHandler(a.Reports)
+	imports := v1.NewImportHandler(a.Imports)
+	search := v1.NewSearchHandler(a.Search)
+	audit := v1.NewAuditHandler(a.Audit)
+	trash := v1.NewTrashHandler(a.Trash, time.Duration(a.Config.Database.TrashRetention))
+	media := v1.NewMediaHandler(a.Foods, a.Sides, a.Blobs)
+	exports := v1.NewExportHandler(a.Reservations, a.Users, a.Foods)
+	auth := api.NewAuthHandler(a.Users, a.Tokens, a.Metrics, validate)
+
 	r := gin.New()
-	r.Use(gin.Logger())
+	// probes and metric scrapes run every few seconds, they would drown the
+	// traces and the request log
+	probes := []string{"/healthz", "/readyz", "/metrics"}
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Actor())
+	// continues the trace of the caller from the traceparent header
+	r.Use(otelgin.Middleware(a.Config.Tracing.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !slices.Contains(probes, r.URL.Path)
+	})))
+	r.Use(middleware.Logger(probes...))
+	r.Use(middleware.Metrics(a.Metrics))
 	r.Use(config.CORSMiddleware())
 	docs.SwaggerInfo.Title = "Reservation API"
 	docs.SwaggerInfo.Description = "This is a server for managing restaurant with  RestAPI build with Go Gin and Gorm"
 	docs.SwaggerInfo.BasePath = "/api/v1"
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	// meant for scraping from inside the network, keep it off the public proxy
+	r.GET("/metrics", gin.WrapH(a.Metrics.Handler()))
+	r.GET("/healthz", a.Health.Live)
+	r.GET("/readyz", a.Health.Ready)
 
-	apiv1 := r.Group("/api/v1")
-	auth := apiv1.Group("/auth")
-	auth.POST("/signin", api.Login)
-	auth.POST("/register", api.Register)
-	apiv1.Use(middleware.Auth())
+	apiv1 := r.Group("/api/v1", middleware.Timeout(time.Duration(a.Config.Database.QueryTimeout), a.Config.Database.Timeouts()))
+	authRoutes := apiv1.Group("/auth")
+	authRoutes.POST("/signin", auth.Login)
+	authRoutes.POST("/register", auth.Register)
+	// media is public so images can be embedded directly in <img> tags
+	apiv1.GET("/media/*key", media.GetMedia)
+	apiv1.Use(middleware.Auth(tokens), middleware.Idempotency(a.Idempotency, time.Duration(a.Config.Server.IdempotencyTTL)))
 	{
 		// for authorized user
-		apiv1.GET("/reservations", middleware.IsAuthorized(), v1.GetReservations)
-		apiv1.GET("/reservations/:id", middleware.IsAuthorized(), v1.GetReservation)
-		apiv1.GET("/food", v1.GetFoods)
-		apiv1.GET("/food:id", v1.GetFood)
-		apiv1.GET("/sides", v1.GetSides)
-		apiv1.GET("/sides/:id", v1.GetSide)
-		apiv1.GET("/mealtype", v1.GetMealTypes)
-		apiv1.GET("/mealtype/:id", v1.GetMealType)
-		apiv1.GET("/me", middleware.IsAuthorized(), v1.GetMe)
-		apiv1.GET("/me/qr", middleware.IsAuthorized(), v1.GetMeQR)
-		apiv1.GET("/users/:id", v1.GetUser)
-		apiv1.GET("/users/:id/reservations", middleware.IsAuthorized(), v1.GetUserReservations)
-		apiv1.POST("/reservations", middleware.IsAuthorized(), v1.CreateReservation)
-		apiv1.PUT("/reservations/:id", middleware.IsAuthorized(), v1.UpdateReservation)
-		apiv1.DELETE("/reservations/:id", middleware.IsAuthorized(), v1.DeleteReservation)
+		apiv1.GET("/reservations/:id", middleware.IsAuthorized(tokens), reservations.GetReservation)
+		apiv1.GET("/food", foods.GetFoods)
+		apiv1.GET("/food/:id", foods.GetFood)
+		apiv1.GET("/food/:id/reviews", reviews.GetFoodReviews)
+		apiv1.GET("/sides", sides.GetSides)
+		apiv1.GET("/sides/:id", sides.GetSide)
+		apiv1.GET("/sides/:id/reviews", reviews.GetSideReviews)
+		apiv1.GET("/mealtype", mealTypes.GetMealTypes)
+		apiv1.GET("/mealtype/:id", mealTypes.GetMealType)
+		apiv1.GET("/search", search.Search)
+		apiv1.GET("/me", middleware.IsAuthorized(tokens), users.GetMe)
+		apiv1.GET("/me/qr", middleware.IsAuthorized(tokens), users.GetMeQR)
+		apiv1.GET("/users/:id", users.GetUser)
+		// users may update their own record, admins any
+		apiv1.PUT("/users/:id", users.UpdateUser)
+		apiv1.GET("/users/:id/reservations", middleware.IsAuthorized(tokens), reservations.GetUserReservations)
+		apiv1.POST("/reservations", middleware.IsAuthorized(tokens), reservations.CreateReservation)
+		apiv1.PUT("/reservations/:id", middleware.IsAuthorized(tokens), reservations.UpdateReservation)
+		apiv1.PATCH("/reservations/:id", middleware.IsAuthorized(tokens), reservations.PatchReservation)
+		apiv1.DELETE("/reservations/:id", middleware.IsAuthorized(tokens), reservations.DeleteReservation)
+		apiv1.POST("/reservations/:id/review", middleware.IsAuthorized(tokens), reviews.CreateReview)
 
 		// for admin
 		adminRoutes := apiv1.Group("/")
-		adminRoutes.Use(middleware.Admin())
+		adminRoutes.Use(middleware.Admin(tokens))
 		{
-			adminRoutes.POST("/food", v1.CreateFood)
-			adminRoutes.PUT("/food/:id", v1.UpdateFood)
-			adminRoutes.DELETE("/food/:id", v1.UpdateFood)
+			adminRoutes.GET("/reservations", reservations.GetReservations)
+			adminRoutes.PUT("/reservations/:id/serve", reservations.ServeReservation)
+			adminRoutes.GET("/reports/production", reports.GetProductionReport)
+			adminRoutes.GET("/export/reservations", exports.ExportReservations)
+			adminRoutes.GET("/export/users", exports.ExportUsers)
+			adminRoutes.GET("/export/foods", exports.ExportFoods)
+			adminRoutes.POST("/import/:resource", imports.ImportCSV)
+			adminRoutes.GET("/reviews", reviews.GetReviews)
+			adminRoutes.PUT("/reviews/:id/hide", reviews.HideReview)
+			adminRoutes.PUT("/reviews/:id/unhide", reviews.UnhideReview)
+			adminRoutes.GET("/audit", audit.GetAudit)
+			adminRoutes.GET("/audit/verify", audit.VerifyAudit)
+			adminRoutes.GET("/trash/:resource", trash.GetTrash)
+			adminRoutes.POST("/trash/:resource/:id/restore", trash.RestoreTrash)
+			adminRoutes.DELETE("/trash/:resource", trash.PurgeTrash)
+			adminRoutes.DELETE("/trash", trash.PurgeAllTrash)
+
+			adminRoutes.POST("/food", foods.CreateFood)
+			adminRoutes.PUT("/food/:id", foods.UpdateFood)
+			adminRoutes.PATCH("/food/:id", foods.PatchFood)
+			adminRoutes.DELETE("/food/:id", foods.DeleteFood)
+			adminRoutes.POST("/food/:id/image", media.UploadFoodImage)
 
-			apiv1.PUT("/users/:id", v1.UpdateUser)
-			apiv1.DELETE("/users/:id", v1.DeleteUser)
-			apiv1.POST("/users", v1.CreateUser)
-			apiv1.GET("/users", v1.GetUsers)
+			adminRoutes.PATCH("/users/:id", users.PatchUser)
+			adminRoutes.DELETE("/users/:id", users.DeleteUser)
+			adminRoutes.POST("/users", users.CreateUser)
+			adminRoutes.GET("/users", users.GetUsers)
 
-			adminRoutes.POST("/sides", v1.CreateSides)
-			adminRoutes.PUT("/sides/:id", v1.UpdateSides)
-			adminRoutes.DELETE("/sides/:id", v1.DeleteSides)
+			adminRoutes.POST("/sides", sides.CreateSides)
+			adminRoutes.PUT("/sides/:id", sides.UpdateSides)
+			adminRoutes.PATCH("/sides/:id", sides.PatchSides)
+			adminRoutes.DELETE("/sides/:id", sides.DeleteSides)
+			adminRoutes.POST("/sides/:id/image", media.UploadSidesImage)
 
-			apiv1.POST("/mealtype", v1.CreateMealType)
-			apiv1.PUT("/mealtype/:id", v1.UpdateMealType)
-			apiv1.DELETE("/mealtype/:id", v1.DeleteMealType)
+			adminRoutes.POST("/mealtype", mealTypes.CreateMealType)
+			adminRoutes.PUT("/mealtype/:id", mealTypes.UpdateMealType)
+			adminRoutes.PATCH("/mealtype/:id", mealTypes.PatchMealType)
+			adminRoutes.DELETE("/mealtype/:id", mealTypes.DeleteMealType)
 		}
 	}
 	return r
@@ -0,0 +1,261 @@
+package routers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/app"
+	"github.com/Hamedblue1381/restaurant-reserve/config"
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/routers"
+	"github.com/Hamedblue1381/restaurant-reserve/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/logger"
+)
+
+// server runs the API against the sqlite-memory driver, seeded with one meal type,
+// food and side dish, and an admin.
+type server struct {
+	t      *testing.T
+	router *gin.Engine
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db := config.SetupDBConnection(config.DatabaseConfig{Driver: config.DriverSQLiteMemory})
+	db.Logger = logger.Discard
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	importer := models.NewImportHandler(db)
+	if _, err := importer.Import(ctx, models.ImportMenus, strings.NewReader("meal_type,category,food,quantity\nLunch,Main,Kebab,1\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importer.Import(ctx, models.ImportSides, strings.NewReader("name,quantity\nRice,1\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	admin := models.User{Name: "Admin", Email: "admin@example.com", Password: "password123", Role: "admin"}
+	if err := models.NewUserHandler(db).CreateUser(ctx, &admin); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "secret"
+	cfg.Auth.TokenTTL = config.Duration(time.Hour)
+	cfg.Server.IdempotencyTTL = config.Duration(time.Hour)
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &server{t, routers.UseRouter(app.New(cfg, db, blobs))}
+}
+
+// do sends a JSON request with the token and the If-Match version, when
+// they are set, and decodes the response into out.
+func (s *server) do(method, path, token string, version int, body string, out interface{}) int {
+	s.t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if version > 0 {
+		req.Header.Set("If-Match", fmt.Sprintf(`"%d"`, version))
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	if out != nil && w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: %v: %s", method, path, err, w.Body)
+		}
+	}
+	return w.Code
+}
+
+func (s *server) signIn(email, password string) string {
+	s.t.Helper()
+	var login struct{ Token string }
+	body := fmt.Sprintf(`{"email":%q,"password":%q}`, email, password)
+	if code := s.do(http.MethodPost, "/api/v1/auth/signin", "", 0, body, &login); code != http.StatusOK {
+		s.t.Fatalf("sign in as %s = %d", email, code)
+	}
+	return login.Token
+}
+
+func (s *server) register(name, email string) string {
+	s.t.Helper()
+	var registered struct{ Token string }
+	body := fmt.Sprintf(`{"name":%q,"email":%q,"password":"password123"}`, name, email)
+	if code := s.do(http.MethodPost, "/api/v1/auth/register", "", 0, body, &registered); code != http.StatusOK {
+		s.t.Fatalf("register %s = %d", email, code)
+	}
+	return registered.Token
+}
+
+func TestReady(t *testing.T) {
+	s := newServer(t)
+	if code := s.do(http.MethodGet, "/readyz", "", 0, "", nil); code != http.StatusOK {
+		t.Errorf("readyz = %d, want 200 once the memory database is migrated", code)
+	}
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	s := newServer(t)
+	admin := s.signIn("admin@example.com", "password123")
+	alice := s.register("Alice", "alice@example.com")
+	bob := s.register("Bob", "bob@example.com")
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.RFC3339)
+
+	var foods models.Page[models.Food]
+	if code := s.do(http.MethodGet, "/api/v1/food", alice, 0, "", &foods); code != http.StatusOK || len(foods.Data) != 1 {
+		t.Fatalf("list food = %d with %d foods", code, len(foods.Data))
+	}
+
+	body := fmt.Sprintf(`{"FoodID":%d,"SideID":1,"date":%q}`, foods.Data[0].ID, tomorrow)
+	if code := s.do(http.MethodPost, "/api/v1/reservations", alice, 0, body, nil); code != http.StatusOK {
+		t.Fatalf("reserve = %d", code)
+	}
+	if code := s.do(http.MethodPost, "/api/v1/reservations", alice, 0, `{"FoodID":99,"date":"`+tomorrow+`"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("reserve a missing food = %d, want 400", code)
+	}
+
+	var reservation models.Reservation
+	if code := s.do(http.MethodGet, "/api/v1/reservations/1", alice, 0, "", &reservation); code != http.StatusOK || reservation.Status != models.ReservationReserved {
+		t.Fatalf("get own reservation = %d %+v", code, reservation)
+	}
+	if code := s.do(http.MethodGet, "/api/v1/reservations/1", bob, 0, "", nil); code != http.StatusForbidden {
+		t.Errorf("get another user's reservation = %d, want 403", code)
+	}
+
+	var page models.Page[models.Reservation]
+	if code := s.do(http.MethodGet, "/api/v1/users/2/reservations", alice, 0, "", &page); code != http.StatusOK || len(page.Data) != 1 {
+		t.Errorf("list own reservations = %d with %d reservations", code, len(page.Data))
+	}
+	if code := s.do(http.MethodGet, "/api/v1/users/2/reservations", bob, 0, "", nil); code != http.StatusForbidden {
+		t.Errorf("list another user's reservations = %d, want 403", code)
+	}
+	if code := s.do(http.MethodGet, "/api/v1/reservations", bob, 0, "", nil); code != http.StatusForbidden {
+		t.Errorf("list every reservation as user = %d, want 403", code)
+	}
+	if code := s.do(http.MethodGet, "/api/v1/reservations", admin, 0, "", &page); code != http.StatusOK || len(page.Data) != 1 {
+		t.Errorf("list every reservation as admin = %d with %d reservations", code, len(page.Data))
+	}
+
+	if code := s.do(http.MethodPatch, "/api/v1/reservations/1", alice, int(reservation.Version), `{"date":"2001-01-01T00:00:00Z"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("patch to a past date = %d, want 400", code)
+	}
+	if code := s.do(http.MethodPatch, "/api/v1/reservations/1", alice, int(reservation.Version), `{"is_paid":true}`, nil); code != http.StatusForbidden {
+		t.Errorf("patch is_paid as user = %d, want 403", code)
+	}
+	if code := s.do(http.MethodDelete, "/api/v1/reservations/1", alice, int(reservation.Version)+1, "", nil); code != http.StatusPreconditionFailed {
+		t.Errorf("cancel with a stale version = %d, want 412", code)
+	}
+	if code := s.do(http.MethodDelete, "/api/v1/reservations/1", alice, int(reservation.Version), "", nil); code != http.StatusNoContent {
+		t.Fatalf("cancel = %d", code)
+	}
+	if code := s.do(http.MethodGet, "/api/v1/reservations/1", alice, 0, "", nil); code != http.StatusNotFound {
+		t.Errorf("get cancelled reservation = %d, want 404", code)
+	}
+
+	var report []models.ProductionRow
+	if code := s.do(http.MethodGet, "/api/v1/reports/production", admin, 0, "", &report); code != http.StatusOK {
+		t.Fatalf("production report = %d", code)
+	}
+	var cancelled int64
+	for _, row := range report {
+		if row.ItemType == "food" {
+			cancelled += row.Cancelled
+		}
+	}
+	if cancelled != 1 {
+		t.Errorf("report counts %d cancelled, want 1: %+v", cancelled, report)
+	}
+	if code := s.do(http.MethodGet, "/api/v1/reports/production", alice, 0, "", nil); code != http.StatusForbidden {
+		t.Errorf("production report as user = %d, want 403", code)
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	s := newServer(t)
+	admin := s.signIn("admin@example.com", "password123")
+
+	var user map[string]interface{}
+	body := `{"name":"Carol","email":"carol@example.com","password":"password123"}`
+	if code := s.do(http.MethodPost, "/api/v1/users", admin, 0, body, &user); code != http.StatusCreated {
+		t.Fatalf("create user = %d", code)
+	}
+	if _, ok := user["password"]; ok {
+		t.Errorf("created user is returned with the password hash: %v", user)
+	}
+	s.signIn("carol@example.com", "password123")
+
+	if code := s.do(http.MethodPost, "/api/v1/users", admin, 0, `{"name":"Dave","email":"dave@example.com"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("create user without a password = %d, want 400", code)
+	}
+}
+
+func TestCatalogWrites(t *testing.T) {
+	s := newServer(t)
+	admin := s.signIn("admin@example.com", "password123")
+	alice := s.register("Alice", "alice@example.com")
+
+	if code := s.do(http.MethodPost, "/api/v1/mealtype", alice, 0, `{"name":"Dinner"}`, nil); code != http.StatusForbidden {
+		t.Errorf("create meal type as user = %d, want 403", code)
+	}
+
+	var mealType models.MealType
+	if code := s.do(http.MethodPost, "/api/v1/mealtype", admin, 0, `{"name":"Dinner"}`, &mealType); code != http.StatusCreated {
+		t.Fatalf("create meal type = %d", code)
+	}
+	var food models.Food
+	body := fmt.Sprintf(`{"name":"Soup","CategoryID":1,"MealTypeID":%d}`, mealType.ID)
+	if code := s.do(http.MethodPost, "/api/v1/food", admin, 0, body, &food); code != http.StatusCreated {
+		t.Fatalf("create food = %d", code)
+	}
+	if code := s.do(http.MethodPatch, "/api/v1/food/"+fmt.Sprint(food.ID), admin, int(food.Version), `{"MealTypeID":99}`, nil); code != http.StatusBadRequest {
+		t.Errorf("patch food to a missing meal type = %d, want 400", code)
+	}
+	if code := s.do(http.MethodPatch, "/api/v1/food/"+fmt.Sprint(food.ID), admin, int(food.Version), `{"description":"Hot"}`, &food); code != http.StatusOK || food.Description != "Hot" {
+		t.Errorf("patch food = %d %+v", code, food)
+	}
+
+	var foods models.Page[models.Food]
+	if code := s.do(http.MethodGet, "/api/v1/food?name=Soup", alice, 0, "", &foods); code != http.StatusOK || len(foods.Data) != 1 || foods.Data[0].Description != "Hot" {
+		t.Errorf("list food = %d %+v", code, foods.Data)
+	}
+}
+
+func TestReserveWithoutSide(t *testing.T) {
+	s := newServer(t)
+	alice := s.register("Alice", "alice@example.com")
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.RFC3339)
+
+	if code := s.do(http.MethodPost, "/api/v1/reservations", alice, 0, `{"FoodID":1,"date":"`+tomorrow+`"}`, nil); code != http.StatusOK {
+		t.Fatalf("reserve without a side = %d", code)
+	}
+	var reservation models.Reservation
+	if code := s.do(http.MethodGet, "/api/v1/reservations/1", alice, 0, "", &reservation); code != http.StatusOK || reservation.SideID != nil {
+		t.Fatalf("get reservation without a side = %d %+v", code, reservation)
+	}
+	if code := s.do(http.MethodPost, "/api/v1/reservations", alice, 0, `{"FoodID":1,"SideID":99,"date":"`+tomorrow+`"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("reserve a missing side = %d, want 400", code)
+	}
+
+	if code := s.do(http.MethodPatch, "/api/v1/reservations/1", alice, int(reservation.Version), `{"SideID":1}`, &reservation); code != http.StatusOK || reservation.SideID == nil || *reservation.SideID != 1 {
+		t.Fatalf("patch in a side = %d %+v", code, reservation)
+	}
+	if code := s.do(http.MethodPatch, "/api/v1/reservations/1", alice, int(reservation.Version), `{"SideID":null}`, &reservation); code != http.StatusOK || reservation.SideID != nil {
+		t.Errorf("patch out the side = %d %+v", code, reservation)
+	}
+}
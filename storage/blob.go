@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobInfo describes a stored blob.
+type BlobInfo struct {
+	Size        int64
+	ModTime     time.Time
+	ContentType string
+}
+
+// BlobStore persists binary objects such as uploaded images under a key.
+type BlobStore interface {
+	Put(key string, r io.Reader) error
+	Get(key string) (io.ReadCloser, *BlobInfo, error)
+	Delete(key string) error
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	spanKey   = "tracing:span"
+	parentKey = "tracing:parent"
+)
+
+// Instrument records a span for every query made through db, as a child of
+// the span in the context the query runs with.
+func Instrument(db *gorm.DB) error {
+	return db.Use(gormPlugin{otel.Tracer(tracerName)})
+}
+
+// gormPlugin wraps each GORM operation in a span.
+type gormPlugin struct {
+	tracer trace.Tracer
+}
+
+func (gormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", p.start("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", end),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", p.start("query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", end),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", p.start("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", end),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", p.start("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", end),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", p.start("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", end),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", p.start("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", end),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p gormPlugin) start(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		// the model is parsed before the callbacks run, so the table is known
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		ctx, span := p.tracer.Start(parent, operation+" "+table,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(db.Dialector.Name()),
+				semconv.DBOperation(operation),
+				semconv.DBSQLTable(table),
+			))
+		// the query runs with the span, so drivers that trace can nest under it
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+		db.InstanceSet(parentKey, parent)
+	}
+}
+
+func end(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+	// a statement can be reused for another query, which must not nest
+	// under this one
+	if parent, ok := db.InstanceGet(parentKey); ok {
+		db.Statement.Context = parent.(context.Context)
+	}
+
+	span.SetAttributes(
+		semconv.DBStatement(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
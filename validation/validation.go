@@ -0,0 +1,150 @@
+// Package validation checks request bodies against the binding tags of their
+// structs, including the custom rules of this API.
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Stores looks up the resources referenced by exists tags.
+type Stores struct {
+	Foods     models.FoodStore
+	Sides     models.SidesStore
+	MealTypes models.MealTypeStore
+}
+
+// Validator validates structs by their binding tags. It names failed fields
+// as they appear in JSON and knows these rules besides the built in ones:
+//
+//   - phone: a telephone number of 7 to 15 digits, optionally starting with +
+//     and grouped with spaces, dashes, dots or parentheses.
+//   - notpast: a time that is not before the start of today.
+//   - exists=food|side|mealtype: the ID of an existing food, side dish or
+//     meal type, looked up in the stores with the context of the validation.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New returns a Validator that looks references up in stores.
+func New(stores Stores) *Validator {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(jsonName)
+	must(v.RegisterValidation("phone", phone))
+	must(v.RegisterValidation("notpast", notPast))
+	must(v.RegisterValidationCtx("exists", stores.exists))
+	return &Validator{v}
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Bind decodes the JSON body of the request into obj and validates it, so
+// references are looked up within the request.
+func (v *Validator) Bind(c *gin.Context, obj interface{}) error {
+	if err := c.ShouldBindWith(obj, decodeJSON{}); err != nil {
+		return err
+	}
+	return v.Struct(c.Request.Context(), obj)
+}
+
+// Struct validates every field of obj.
+func (v *Validator) Struct(ctx context.Context, obj interface{}) error {
+	return v.validate.StructCtx(ctx, obj)
+}
+
+// StructPartial validates the named fields of obj, given by their Go names.
+func (v *Validator) StructPartial(ctx context.Context, obj interface{}, fields ...string) error {
+	return v.validate.StructPartialCtx(ctx, obj, fields...)
+}
+
+// decodeJSON decodes like binding.JSON, but leaves validation to the
+// Validator, which gin's binding cannot pass the request context to.
+type decodeJSON struct{}
+
+func (decodeJSON) Name() string {
+	return "json"
+}
+
+func (decodeJSON) Bind(req *http.Request, obj interface{}) error {
+	if req == nil || req.Body == nil {
+		return errors.New("invalid request")
+	}
+	decoder := json.NewDecoder(req.Body)
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(obj)
+}
+
+// jsonName names a field by its json tag. Fields without one keep their Go
+// name.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ().-]*[0-9]$`)
+
+func phone(fl validator.FieldLevel) bool {
+	number := fl.Field().String()
+	if !phonePattern.MatchString(number) {
+		return false
+	}
+	digits := 0
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+func notPast(fl validator.FieldLevel) bool {
+	date, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return !date.Before(today)
+}
+
+func (s Stores) exists(ctx context.Context, fl validator.FieldLevel) bool {
+	id := uint(fl.Field().Uint())
+	if id == 0 {
+		return false
+	}
+
+	var err error
+	switch fl.Param() {
+	case "food":
+		_, err = s.Foods.GetFood(ctx, id)
+	case "side":
+		_, err = s.Sides.GetSide(ctx, id)
+	case "mealtype":
+		_, err = s.MealTypes.GetMealType(ctx, id)
+	default:
+		panic("validation: unknown exists resource " + fl.Param())
+	}
+	// other errors are left to the write, which fails on a missing reference
+	return !errors.Is(err, models.ErrNotFound)
+}
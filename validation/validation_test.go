@@ -0,0 +1,116 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hamedblue1381/restaurant-reserve/models"
+	"github.com/Hamedblue1381/restaurant-reserve/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// foods finds the food with ID 1 only, and records the context of the last
+// lookup.
+type foods struct {
+	models.FoodStore
+	ctx context.Context
+}
+
+func (f *foods) GetFood(ctx context.Context, id uint) (*models.Food, error) {
+	f.ctx = ctx
+	if id != 1 {
+		return nil, models.ErrNotFound
+	}
+	return &models.Food{Model: models.Model{ID: id}}, nil
+}
+
+var validate = validation.New(validation.Stores{Foods: &foods{}})
+
+// invalid validates v and returns the JSON names of the rejected fields with
+// their failed tags.
+func invalid(t *testing.T, v interface{}) map[string]string {
+	t.Helper()
+	err := validate.Struct(context.Background(), v)
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct() = %v, want validation errors", err)
+	}
+	fields := make(map[string]string, len(errs))
+	for _, field := range errs {
+		fields[field.Field()] = field.Tag()
+	}
+	return fields
+}
+
+func TestPhone(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{"", true},
+		{"+98 912 345 6789", true},
+		{"021 (555) 0100", true},
+		{"0912.345.6789", true},
+		{"12345", false},
+		{"+1234567890123456", false},
+		{"call me", false},
+		{"0912-", false},
+	}
+	for _, tt := range tests {
+		user := models.UserDetails{Name: "a", Email: "a@example.com", Telephone: tt.number}
+		fields := invalid(t, &user)
+		if valid := fields["telephone"] != "phone"; valid != tt.valid {
+			t.Errorf("telephone %q valid = %v, want %v", tt.number, valid, tt.valid)
+		}
+	}
+}
+
+func TestNotPast(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		date  time.Time
+		valid bool
+	}{
+		{now, true},
+		{time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), true},
+		{now.AddDate(0, 0, 7), true},
+		{now.AddDate(0, 0, -1), false},
+	}
+	for _, tt := range tests {
+		reservation := models.Reservation{FoodID: 1, Date: tt.date}
+		fields := invalid(t, &reservation)
+		if valid := fields["date"] == ""; valid != tt.valid {
+			t.Errorf("date %v valid = %v, want %v (%v)", tt.date, valid, tt.valid, fields)
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	if fields := invalid(t, &models.Reservation{FoodID: 1, Date: tomorrow}); fields != nil {
+		t.Errorf("existing food rejected: %v", fields)
+	}
+	if got := invalid(t, &models.Reservation{FoodID: 2, Date: tomorrow})["FoodID"]; got != "exists" {
+		t.Errorf("missing food failed %q, want exists", got)
+	}
+}
+
+type key struct{}
+
+func TestExistsContext(t *testing.T) {
+	store := &foods{}
+	v := validation.New(validation.Stores{Foods: store})
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	reservation := models.Reservation{FoodID: 1, Date: time.Now()}
+	if err := v.Struct(ctx, &reservation); err != nil {
+		t.Fatal(err)
+	}
+	if store.ctx == nil || store.ctx.Value(key{}) != "request" {
+		t.Errorf("food looked up with %v, want the context of the validation", store.ctx)
+	}
+}